@@ -20,13 +20,15 @@ const exitCodeInvalid = 2
 
 // Reusable flags used by the build pipeline.
 var (
-	v       = flag.Bool("v", false, "print all tasks as they are run")
-	dryRun  = flag.Bool("dry-run", false, "print all tasks without executing actions")
-	longRun = flag.Duration("long-run", time.Minute, "print when a task takes longer")
-	noDeps  = flag.Bool("no-deps", false, "do not process dependencies")
-	skip    = flag.String("skip", "", "skip processing the `comma-separated tasks`")
-	noColor = flag.Bool("no-color", false, "disable colorizing output")
-	graph   = flag.Bool("graph", false, "output the task dependency graph in DOT format and exit")
+	v         = flag.Bool("v", false, "print all tasks as they are run")
+	dryRun    = flag.Bool("dry-run", false, "print all tasks without executing actions")
+	longRun   = flag.Duration("long-run", time.Minute, "print when a task takes longer")
+	heartbeat = flag.Duration("heartbeat", 0, "print periodically that a task is still running (0 disables)")
+	noDeps    = flag.Bool("no-deps", false, "do not process dependencies")
+	skip      = flag.String("skip", "", "skip processing the `comma-separated tasks`")
+	noColor   = flag.Bool("no-color", false, "disable colorizing output")
+	logLevel  = flag.String("log-level", "", "minimum `level` of logged records: debug, info, or warn")
+	graph     = flag.Bool("graph", false, "output the task dependency graph in DOT format and exit")
 )
 
 // Main is an extension of goyek.Main which additionally defines reusable flags
@@ -34,7 +36,9 @@ var (
 //
 // It automatically sets up:
 //   - Colored output via [color.ReportFlow] and [color.ReportStatus]
+//   - CI specific task reporting via [color.ReportCI]
 //   - Standard middlewares ([middleware.BufferParallel], [middleware.SilentNonFailed], [middleware.ReportLongRun])
+//   - Periodic heartbeats of long-running tasks via [color.ReportHeartbeat] if -heartbeat is set
 //   - Command line flags for common options (-v, -dry-run, -long-run, etc.)
 //
 // The command line syntax is: [tasks] [flags] [--] [args]
@@ -61,23 +65,38 @@ func Main() {
 		*v = true // needed to report the task status
 	}
 
+	if *logLevel != "" {
+		lvl, err := color.ParseLevel(*logLevel)
+		if err != nil {
+			fmt.Fprintln(goyek.Output(), err)
+			os.Exit(exitCodeInvalid)
+		}
+		color.SetLogLevel(lvl)
+	}
+
 	goyek.UseExecutor(color.ReportFlow)
 
 	if *dryRun {
 		goyek.Use(middleware.DryRun)
 	}
 	goyek.Use(color.ReportStatus)
-	if *v {
-		goyek.Use(middleware.BufferParallel)
-	} else {
+	if !*v {
 		goyek.Use(middleware.SilentNonFailed)
 	}
+	// The CI reports are not silenced for the passed tasks,
+	// but they are buffered so that the reports of parallel tasks are not mixed.
+	goyek.Use(color.ReportCI)
+	goyek.Use(middleware.BufferParallel)
 	if *longRun > 0 {
 		goyek.Use(middleware.ReportLongRun(*longRun))
 	}
+	if *heartbeat > 0 {
+		goyek.Use(color.ReportHeartbeat(*heartbeat))
+	}
 	if *noColor {
 		color.NoColor()
 	}
+	color.SetFlowOutput(goyek.Output())
 
 	var opts []goyek.Option
 	if *noDeps {
@@ -2,10 +2,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/goyek/goyek/v3"
 	"github.com/mattn/go-shellwords"
@@ -16,6 +19,11 @@ type Option func(a *goyek.A, cmd *exec.Cmd)
 
 // Exec runs the command.
 // It calls a.Error[f] and returns false in case of any problems.
+//
+// On Linux, it logs a warning when the command seems to be blocked
+// reading the standard input for 10 seconds, unless the standard input
+// is a terminal. Only the command itself is inspected, so programs
+// it starts, like the ones run by "sh -c", are not detected.
 // Example usage:
 //
 //	cmd.Exec(a, "FOO=foo BAR=baz ./foo --bar=baz", cmd.Dir("pkg"))
@@ -31,8 +39,17 @@ func Exec(a *goyek.A, cmdLine string, opts ...Option) bool {
 		panic("no command provided")
 	}
 
-	cmd := exec.CommandContext(a.Context(), args[0], args[1:]...) //nolint:gosec // it is a convenient function to run programs
-	cmd.Stdin = os.Stdin
+	cmd := command(a, args[0], args[1:], envs, opts)
+	return run(a, cmd)
+}
+
+// command creates the command with the default standard streams and
+// environment, and then applies the options.
+func command(a *goyek.A, name string, args, envs []string, opts []Option) *exec.Cmd {
+	cmd := exec.CommandContext(a.Context(), name, args...) //nolint:gosec // it is a convenient function to run programs
+	if stdin := defaultStdin(); stdin != nil {
+		cmd.Stdin = stdin
+	}
 	cmd.Stdout = a.Output()
 	cmd.Stderr = a.Output()
 	cmd.Env = os.Environ()
@@ -40,14 +57,31 @@ func Exec(a *goyek.A, cmdLine string, opts ...Option) bool {
 	for _, opt := range opts {
 		opt(a, cmd)
 	}
+	return cmd
+}
 
-	if err := cmd.Run(); err != nil {
+// run runs the command.
+// It calls a.Error and returns false in case of any problems.
+func run(a *goyek.A, cmd *exec.Cmd) bool {
+	a.Helper()
+	if err := startWait(a, cmd); err != nil {
 		a.Error(err)
 		return false
 	}
 	return true
 }
 
+// startWait starts the command and waits for it to finish
+// while watching whether it is blocked reading the standard input.
+func startWait(a *goyek.A, cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	stop := watchStdin(a, cmd)
+	defer stop()
+	return cmd.Wait()
+}
+
 // Dir is an option to set the working directory.
 func Dir(s string) Option {
 	return func(_ *goyek.A, cmd *exec.Cmd) {
@@ -89,7 +123,43 @@ func ClearEnv() Option {
 	}
 }
 
+// Timeout is an option to kill the command if it does not finish
+// within the duration. The timeout is reported using a.Errorf.
+func Timeout(d time.Duration) Option {
+	return func(a *goyek.A, cmd *exec.Cmd) {
+		if cmd.Err != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(a.Context(), d)
+		a.Cleanup(cancel)
+		// The context of a command can be set only when creating it.
+		timed := exec.CommandContext(ctx, cmd.Path) //nolint:gosec // the command is already configured
+		timed.Path = cmd.Path
+		timed.Args = cmd.Args
+		timed.Env = cmd.Env
+		timed.Dir = cmd.Dir
+		timed.Stdin = cmd.Stdin
+		timed.Stdout = cmd.Stdout
+		timed.Stderr = cmd.Stderr
+		timed.ExtraFiles = cmd.ExtraFiles
+		timed.SysProcAttr = cmd.SysProcAttr
+		timed.Err = cmd.Err
+		timed.Cancel = func() error {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && a.Context().Err() == nil {
+				a.Errorf("command timed out after %v", d)
+			}
+			return cmd.Process.Kill()
+		}
+		// Do not wait for the programs started by the command
+		// which keep the output open.
+		timed.WaitDelay = time.Second
+		*cmd = *timed
+	}
+}
+
 // Stdin is an option to set the standard input.
+// Use Stdin(os.Stdin) to let the command read the standard input
+// of the process when NoStdin is in effect.
 func Stdin(r io.Reader) Option {
 	return func(_ *goyek.A, cmd *exec.Cmd) {
 		cmd.Stdin = r
@@ -3,8 +3,10 @@ package cmd
 import (
 	"context"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/goyek/goyek/v3"
 )
@@ -175,3 +177,25 @@ func TestExec_EnvOnly(t *testing.T) {
 	}()
 	Exec(&goyek.A{}, "FOO=bar")
 }
+
+func TestExec_Timeout(t *testing.T) {
+	dir := t.TempDir()
+	f := &goyek.Flow{}
+	var output strings.Builder
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Exec(a, `sh -c "echo $GOYEK_TEST_VAR; pwd"`, Env("GOYEK_TEST_VAR", "set"), Dir(dir),
+				Timeout(time.Minute), Stdout(&output))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The directory may be reported with symbolic links resolved.
+	if got := output.String(); !strings.HasPrefix(got, "set\n") || !strings.HasSuffix(got, filepath.Base(dir)+"\n") {
+		t.Errorf("got %q, want the variable and the directory", got)
+	}
+}
@@ -3,6 +3,7 @@ package cmd_test
 import (
 	"io"
 	"strings"
+	"time"
 
 	"github.com/goyek/goyek/v3"
 
@@ -25,3 +26,16 @@ func ExampleExec() {
 		},
 	})
 }
+
+func ExampleExpect() {
+	goyek.Define(goyek.Task{
+		Name:  "smoke",
+		Usage: "smoke test the binary",
+		Action: func(a *goyek.A) {
+			cmd.Expect(a, "./app --version").
+				StdoutMatches(`^app v\d+\.\d+\.\d+`).
+				ExitCode(0).
+				Within(5 * time.Second)
+		},
+	})
+}
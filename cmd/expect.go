@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/mattn/go-shellwords"
+
+	"github.com/goyek/x/internal/diff"
+)
+
+// Expectation holds the outcome of a command run by Expect.
+// Its methods check the outcome and report each failed expectation
+// using a.Errorf. The methods return the Expectation so that they can be chained.
+type Expectation struct {
+	a        *goyek.A
+	ran      bool
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+	exitCode int
+	duration time.Duration
+}
+
+// Expect runs the command like Exec and returns its outcome for checking.
+// The standard output and standard error are captured and also written
+// to the task output or to the writers set by the Stdout and Stderr options.
+// A non-zero exit code is not reported unless checked using ExitCode.
+// Use the Timeout option to stop a command which hangs.
+// Example usage:
+//
+//	cmd.Expect(a, "./app --version", cmd.Timeout(5*time.Second)).StdoutMatches(`^app v\d+`).ExitCode(0)
+func Expect(a *goyek.A, cmdLine string, opts ...Option) *Expectation {
+	a.Helper()
+	e := &Expectation{a: a, exitCode: -1}
+
+	envs, args, err := shellwords.ParseWithEnvs(cmdLine)
+	if err != nil {
+		a.Error("parse command line: ", err)
+		return e
+	}
+	if len(args) == 0 {
+		panic("no command provided")
+	}
+
+	cmd := command(a, args[0], args[1:], envs, opts)
+	// Both streams may share the same destination.
+	mu := &sync.Mutex{}
+	cmd.Stdout = &lockedWriter{mu: mu, w: teeWriter(cmd.Stdout, &e.stdout)}
+	cmd.Stderr = &lockedWriter{mu: mu, w: teeWriter(cmd.Stderr, &e.stderr)}
+
+	start := time.Now()
+	err = startWait(a, cmd)
+	e.duration = time.Since(start)
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		a.Error(err)
+		return e
+	}
+	e.exitCode = cmd.ProcessState.ExitCode()
+	e.ran = true
+	return e
+}
+
+// ExitCode checks the exit code of the command.
+func (e *Expectation) ExitCode(want int) *Expectation {
+	e.a.Helper()
+	if e.ran && e.exitCode != want {
+		e.a.Errorf("got exit code %d, want %d", e.exitCode, want)
+	}
+	return e
+}
+
+// Stdout checks that the standard output is equal to the text.
+func (e *Expectation) Stdout(want string) *Expectation {
+	e.a.Helper()
+	e.equal("stdout", e.stdout.String(), want)
+	return e
+}
+
+// StdoutContains checks that the standard output contains the text.
+func (e *Expectation) StdoutContains(substr string) *Expectation {
+	e.a.Helper()
+	e.contains("stdout", e.stdout.String(), substr)
+	return e
+}
+
+// StdoutMatches checks that the standard output matches the regular expression.
+func (e *Expectation) StdoutMatches(expr string) *Expectation {
+	e.a.Helper()
+	e.matches("stdout", e.stdout.String(), expr)
+	return e
+}
+
+// Stderr checks that the standard error is equal to the text.
+func (e *Expectation) Stderr(want string) *Expectation {
+	e.a.Helper()
+	e.equal("stderr", e.stderr.String(), want)
+	return e
+}
+
+// StderrContains checks that the standard error contains the text.
+func (e *Expectation) StderrContains(substr string) *Expectation {
+	e.a.Helper()
+	e.contains("stderr", e.stderr.String(), substr)
+	return e
+}
+
+// StderrMatches checks that the standard error matches the regular expression.
+func (e *Expectation) StderrMatches(expr string) *Expectation {
+	e.a.Helper()
+	e.matches("stderr", e.stderr.String(), expr)
+	return e
+}
+
+// Within checks that the command took at most the duration.
+// It is checked after the command finished, so it does not stop
+// a command which hangs. Use the Timeout option for that.
+func (e *Expectation) Within(d time.Duration) *Expectation {
+	e.a.Helper()
+	if e.ran && e.duration > d {
+		e.a.Errorf("command took %v, want within %v", e.duration, d)
+	}
+	return e
+}
+
+func (e *Expectation) equal(name, got, want string) {
+	e.a.Helper()
+	if e.ran && got != want {
+		e.a.Errorf("%s mismatch (-want +got):\n%s", name, diff.Unified("want", "got", want, got))
+	}
+}
+
+func (e *Expectation) contains(name, got, substr string) {
+	e.a.Helper()
+	if e.ran && !strings.Contains(got, substr) {
+		e.a.Errorf("%s does not contain %q:\n%s", name, substr, quoteOutput(got))
+	}
+}
+
+func (e *Expectation) matches(name, got, expr string) {
+	e.a.Helper()
+	if !e.ran {
+		return
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		e.a.Errorf("invalid %s pattern: %v", name, err)
+		return
+	}
+	if !re.MatchString(got) {
+		e.a.Errorf("%s does not match %q:\n%s", name, expr, quoteOutput(got))
+	}
+}
+
+// quoteOutput indents the output so that it stands out in the report.
+func quoteOutput(s string) string {
+	if s == "" {
+		return "\t(empty)"
+	}
+	s = strings.TrimSuffix(s, "\n")
+	return "\t" + strings.ReplaceAll(s, "\n", "\n\t")
+}
+
+func teeWriter(w, capture io.Writer) io.Writer {
+	if w == nil {
+		return capture
+	}
+	return io.MultiWriter(w, capture)
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
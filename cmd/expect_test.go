@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+func TestExpect_Pass(t *testing.T) {
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Expect(a, `sh -c "echo app v1.2.3; echo warning >&2; exit 3"`).
+				Stdout("app v1.2.3\n").
+				StdoutContains("v1.2").
+				StdoutMatches(`(?m)^app v\d+\.\d+\.\d+$`).
+				Stderr("warning\n").
+				StderrContains("warn").
+				StderrMatches("^warn").
+				ExitCode(3).
+				Within(time.Minute)
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatalf("unexpected failure: %v\n%s", err, out.String())
+	}
+	if got := out.String(); !strings.Contains(got, "app v1.2.3\n") || !strings.Contains(got, "warning\n") {
+		t.Errorf("output %q does not contain the command output", got)
+	}
+}
+
+func TestExpect_Fail(t *testing.T) {
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Expect(a, `sh -c "echo one; echo two; sleep 0.1"`).
+				Stdout("one\nthree\n").
+				StdoutContains("four").
+				StdoutMatches("^five").
+				StderrMatches("(").
+				ExitCode(1).
+				Within(time.Millisecond)
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err == nil {
+		t.Fatal("expected the flow to fail")
+	}
+	got := out.String()
+	for _, want := range []string{
+		"stdout mismatch (-want +got):",
+		"-three\n",
+		"+two\n",
+		`stdout does not contain "four":`,
+		`stdout does not match "^five":`,
+		"invalid stderr pattern:",
+		"got exit code 0, want 1",
+		"want within 1ms",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestExpect_Timeout(t *testing.T) {
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Expect(a, "sleep 10", Timeout(100*time.Millisecond))
+		},
+	})
+
+	start := time.Now()
+	if err := f.Execute(context.Background(), []string{"test"}); err == nil {
+		t.Error("expected the flow to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the command was not stopped, it took %v", elapsed)
+	}
+	if got := out.String(); !strings.Contains(got, "command timed out after 100ms") {
+		t.Errorf("output %q does not report the timeout", got)
+	}
+}
+
+func TestExpect_NotStarted(t *testing.T) {
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Expect(a, "goyek-x-not-existing-program").ExitCode(0).Stdout("")
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err == nil {
+		t.Fatal("expected the flow to fail")
+	}
+	got := out.String()
+	if !strings.Contains(got, "executable file not found") {
+		t.Errorf("output %q does not contain the start error", got)
+	}
+	if strings.Contains(got, "exit code") {
+		t.Errorf("output %q contains checks of a command that did not run", got)
+	}
+}
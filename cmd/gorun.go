@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/mattn/go-shellwords"
+)
+
+// GoRun builds the Go program using GoTool and runs it like Exec.
+// The first argument of the command line is the package to build
+// instead of the program name.
+// It calls a.Error[f] and returns false in case of any problems.
+// Example usage:
+//
+//	cmd.GoRun(a, "golang.org/x/tools/cmd/stringer@v0.30.0 -type=Status")
+func GoRun(a *goyek.A, cmdLine string, opts ...Option) bool {
+	a.Helper()
+
+	envs, args, err := shellwords.ParseWithEnvs(cmdLine)
+	if err != nil {
+		a.Error("parse command line: ", err)
+		return false
+	}
+	if len(args) == 0 {
+		panic("no package provided")
+	}
+
+	bin, ok := GoTool(a, args[0], opts...)
+	if !ok {
+		return false
+	}
+	cmd := command(a, bin, args[1:], envs, opts)
+	return run(a, cmd)
+}
+
+// GoTool builds the Go program and returns the path to its binary.
+// It calls a.Error[f] and returns false in case of any problems.
+//
+// The package can be:
+//   - a module-versioned package like "golang.org/x/tools/cmd/stringer@v0.30.0",
+//     which is built once per version; queries like "@latest"
+//     or branch names are rebuilt every time,
+//   - a package of a dependency resolved using the go.mod file like
+//     "github.com/golangci/golangci-lint/v2/cmd/golangci-lint",
+//     which is rebuilt only when go.mod or go.sum changes,
+//   - a relative or absolute package path like "./tools/x"
+//     or a package of the main module,
+//     which is rebuilt every time (the Go build cache keeps it fast).
+//
+// The packages of dependencies are also rebuilt every time if go.mod
+// replaces a module with a local directory or a go.work workspace
+// is used, as their sources may change at any time.
+//
+// The binaries are stored in the goyek/bin directory of [os.UserCacheDir].
+// Only the working directory and the environment configured by
+// the options are used when building.
+func GoTool(a *goyek.A, pkg string, opts ...Option) (string, bool) {
+	a.Helper()
+
+	build := goCommand(a, opts)
+	dir, err := binDir()
+	if err != nil {
+		a.Error("go tool cache: ", err)
+		return "", false
+	}
+
+	key, reuse, err := binKey(pkg, build.Dir, build.Env)
+	if err != nil {
+		a.Error("go tool cache: ", err)
+		return "", false
+	}
+	name := binName(pkg, build.Dir)
+	bin := filepath.Join(dir, cacheKey(pkg, key), name)
+	if reuse {
+		if fi, err := os.Stat(bin); err == nil && fi.Mode().IsRegular() {
+			return bin, true
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(bin), 0o750); err != nil {
+		a.Error("go tool cache: ", err)
+		return "", false
+	}
+	// Build into a temporary directory and rename the binary afterwards
+	// so that concurrent builds of the same program do not interfere.
+	tmpDir, err := os.MkdirTemp(dir, "tmp-")
+	if err != nil {
+		a.Error("go tool cache: ", err)
+		return "", false
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck // best effort cleanup
+
+	tmpBin := filepath.Join(tmpDir, name)
+	if strings.Contains(pkg, "@") {
+		build.Args = []string{"go", "install", pkg}
+		build.Env = append(build.Env, "GOBIN="+tmpDir)
+	} else {
+		build.Args = []string{"go", "build", "-o", tmpBin, pkg}
+	}
+	build.Stdout = a.Output()
+	build.Stderr = a.Output()
+	if !run(a, build) {
+		return "", false
+	}
+	if err := os.Rename(tmpBin, bin); err != nil {
+		a.Error("go tool cache: ", err)
+		return "", false
+	}
+	return bin, true
+}
+
+// goCommand returns the go command using the working directory
+// and the environment configured by the options. Other settings,
+// like the standard streams or the sandbox, apply only to the built program.
+func goCommand(a *goyek.A, opts []Option) *exec.Cmd {
+	opt := command(a, "go", nil, nil, opts)
+	build := exec.CommandContext(a.Context(), "go")
+	build.Dir = opt.Dir
+	build.Env = opt.Env
+	return build
+}
+
+// binDir returns the directory containing the built Go programs.
+func binDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "goyek", "bin"), nil
+}
+
+// cacheKey returns the name of the directory for the binary
+// built from the package and the given additional key.
+func cacheKey(pkg, key string) string {
+	h := sha256.New()
+	for _, s := range []string{pkg, key, runtime.Version(), runtime.GOOS, runtime.GOARCH} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	const keyLen = 16
+	return hex.EncodeToString(h.Sum(nil)[:keyLen])
+}
+
+// binKey returns the additional key of the binary built from the package
+// and whether a previously built binary can be reused.
+// The environment is used to find the workspace.
+func binKey(pkg, dir string, env []string) (string, bool, error) {
+	if isLocalPackage(pkg) {
+		// The same relative path may refer to different packages.
+		key, err := filepath.Abs(localPath(pkg, dir))
+		return key, false, err
+	}
+	if _, version, ok := strings.Cut(pkg, "@"); ok {
+		return "", isCanonicalVersion(version), nil
+	}
+	mod, err := findGoModule(dir)
+	if err != nil {
+		return "", false, err
+	}
+	if pkg == mod.path || strings.HasPrefix(pkg, mod.path+"/") || mod.localReplace {
+		// The sources of the main module and the local directories
+		// may change at any time.
+		return mod.root, false, nil
+	}
+	workspace, err := inWorkspace(dir, env)
+	if err != nil || workspace {
+		// The sources of the workspace modules may change at any time.
+		return mod.root, false, err
+	}
+	return mod.hash, true, nil
+}
+
+// goModule describes the module containing a directory.
+type goModule struct {
+	root         string // directory containing the go.mod file
+	path         string // module path
+	hash         string // hash of go.mod and go.sum
+	localReplace bool   // whether a module is replaced with a local directory
+}
+
+// findGoModule returns the module containing the directory.
+func findGoModule(dir string) (goModule, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return goModule{}, err
+	}
+	for {
+		mod, err := os.ReadFile(filepath.Join(dir, "go.mod")) //nolint:gosec // reading go.mod of the module is intended
+		if err == nil {
+			sum, err := os.ReadFile(filepath.Join(dir, "go.sum")) //nolint:gosec // reading go.sum of the module is intended
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return goModule{}, err
+			}
+			h := sha256.New()
+			h.Write(mod)
+			h.Write([]byte{0})
+			h.Write(sum)
+			return goModule{
+				root:         dir,
+				path:         modulePath(mod),
+				hash:         hex.EncodeToString(h.Sum(nil)),
+				localReplace: hasLocalReplace(mod),
+			}, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return goModule{}, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return goModule{}, errors.New("go.mod file not found")
+		}
+		dir = parent
+	}
+}
+
+// modulePath returns the module path declared in the go.mod file.
+func modulePath(mod []byte) string {
+	for _, line := range strings.Split(string(mod), "\n") {
+		fields := strings.Fields(line)
+		const moduleFields = 2
+		if len(fields) < moduleFields || fields[0] != "module" {
+			continue
+		}
+		if path, err := strconv.Unquote(fields[1]); err == nil {
+			return path
+		}
+		return fields[1]
+	}
+	return ""
+}
+
+// hasLocalReplace reports whether the go.mod file replaces
+// a module with a local directory.
+func hasLocalReplace(mod []byte) bool {
+	inBlock := false
+	for _, line := range strings.Split(string(mod), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case fields[0] == "replace(" || fields[0] == "replace" && len(fields) > 1 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "replace":
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+		for i, f := range fields {
+			if f == "=>" && i+1 < len(fields) && isLocalPackage(fields[i+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inWorkspace reports whether the go command run in the directory
+// with the environment uses a go.work file.
+func inWorkspace(dir string, env []string) (bool, error) {
+	switch getenv(env, "GOWORK") {
+	case "off":
+		return false, nil
+	case "":
+	default:
+		return true, nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+			return true, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false, nil
+		}
+		dir = parent
+	}
+}
+
+// getenv returns the value of the environment variable
+// like the command using the environment sees it.
+func getenv(env []string, key string) string {
+	if env == nil {
+		return os.Getenv(key)
+	}
+	for i := len(env) - 1; i >= 0; i-- {
+		if v, ok := strings.CutPrefix(env[i], key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// canonicalVersion matches semantic versions including pseudo-versions.
+var canonicalVersion = regexp.MustCompile(`^v(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(\+incompatible)?$`)
+
+// isCanonicalVersion reports whether the version always refers
+// to the same code, unlike queries like "latest" or branch names.
+func isCanonicalVersion(version string) bool {
+	return canonicalVersion.MatchString(version)
+}
+
+// isLocalPackage reports whether the package is given as a file system path.
+func isLocalPackage(pkg string) bool {
+	return pkg == "." || pkg == ".." ||
+		strings.HasPrefix(pkg, "./") || strings.HasPrefix(pkg, "../") ||
+		strings.HasPrefix(pkg, `.\`) || strings.HasPrefix(pkg, `..\`) ||
+		filepath.IsAbs(pkg)
+}
+
+// localPath returns the path of the local package relative to the directory.
+func localPath(pkg, dir string) string {
+	if filepath.IsAbs(pkg) {
+		return pkg
+	}
+	return filepath.Join(dir, pkg)
+}
+
+// binName returns the name of the binary built from the package
+// following the naming used by go install.
+// Local packages are relative to the given directory.
+func binName(pkg, dir string) string {
+	pkg, _, _ = strings.Cut(pkg, "@")
+	if isLocalPackage(pkg) {
+		if abs, err := filepath.Abs(localPath(pkg, dir)); err == nil {
+			pkg = abs
+		}
+	}
+	pkg = filepath.ToSlash(pkg)
+	name := path.Base(pkg)
+	if isMajorVersion(name) {
+		if parent := path.Base(path.Dir(pkg)); parent != "." && parent != "/" {
+			name = parent
+		}
+	}
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// isMajorVersion reports whether the path element is a major version suffix like v2.
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' || s == "v0" || s == "v1" {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+func TestGoRun(t *testing.T) {
+	setupGoToolCache(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/hello\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "main.go"), `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	fmt.Println("hello " + strings.Join(os.Args[1:], " ") + " " + os.Getenv("GREETING"))
+}
+`)
+
+	f := &goyek.Flow{}
+	var output strings.Builder
+	var bins []string
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			for range 2 {
+				bin, ok := GoTool(a, "example.com/hello", Dir(dir))
+				if !ok {
+					return
+				}
+				bins = append(bins, bin)
+			}
+			GoRun(a, "GREETING=hi example.com/hello world", Dir(dir), Stdout(&output))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := output.String(), "hello world hi\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+	if len(bins) != 2 || bins[0] != bins[1] {
+		t.Fatalf("expected the same binary for both builds, got: %v", bins)
+	}
+	if got := filepath.Base(bins[0]); !strings.HasPrefix(got, "hello") {
+		t.Errorf("got binary name %q, want hello", got)
+	}
+}
+
+func TestGoTool_Cached(t *testing.T) {
+	setupGoToolCache(t)
+	dir := t.TempDir()
+	// The vendored dependency is built without downloading it.
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.21\n\nrequire example.com/hello v1.0.0\n")
+	writeFile(t, filepath.Join(dir, "vendor", "modules.txt"), "# example.com/hello v1.0.0\n## explicit; go 1.21\nexample.com/hello\n")
+	writeFile(t, filepath.Join(dir, "vendor", "example.com", "hello", "main.go"), "package main\n\nfunc main() {}\n")
+
+	build := func() (string, time.Time) {
+		t.Helper()
+		f := &goyek.Flow{}
+		var bin string
+		f.Define(goyek.Task{
+			Name: "test",
+			Action: func(a *goyek.A) {
+				bin, _ = GoTool(a, "example.com/hello", Dir(dir))
+			},
+		})
+		if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bin, fi.ModTime()
+	}
+
+	bin1, mod1 := build()
+	bin2, mod2 := build()
+	if bin1 != bin2 || !mod1.Equal(mod2) {
+		t.Errorf("binary was rebuilt: %s (%v), %s (%v)", bin1, mod1, bin2, mod2)
+	}
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.22\n\nrequire example.com/hello v1.0.0\n")
+	writeFile(t, filepath.Join(dir, "vendor", "modules.txt"), "# example.com/hello v1.0.0\n## explicit; go 1.22\nexample.com/hello\n")
+	if bin3, _ := build(); bin3 == bin1 {
+		t.Error("binary was not rebuilt after go.mod change")
+	}
+}
+
+func TestGoRun_MainModule(t *testing.T) {
+	setupGoToolCache(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/hello\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "cmd", "hello", "main.go"), helloProgram("one"))
+
+	run := func() string {
+		t.Helper()
+		f := &goyek.Flow{}
+		var output strings.Builder
+		f.Define(goyek.Task{
+			Name: "test",
+			Action: func(a *goyek.A) {
+				GoRun(a, "example.com/hello/cmd/hello", Dir(dir), Stdout(&output))
+			},
+		})
+		if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+			t.Fatal(err)
+		}
+		return output.String()
+	}
+
+	if got := run(); got != "one\n" {
+		t.Fatalf("got output %q, want %q", got, "one\n")
+	}
+	writeFile(t, filepath.Join(dir, "cmd", "hello", "main.go"), helloProgram("two"))
+	if got := run(); got != "two\n" {
+		t.Errorf("stale binary after the source change: got output %q, want %q", got, "two\n")
+	}
+}
+
+func TestGoRun_LocalReplace(t *testing.T) {
+	setupGoToolCache(t)
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app")
+	writeFile(t, filepath.Join(app, "go.mod"), "module example.com/app\n\ngo 1.21\n\n"+
+		"require example.com/hello v0.0.0\n\nreplace example.com/hello => ../hello\n")
+	writeFile(t, filepath.Join(dir, "hello", "go.mod"), "module example.com/hello\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "hello", "main.go"), helloProgram("one"))
+
+	if got := goRunHello(t, app); got != "one\n" {
+		t.Fatalf("got output %q, want %q", got, "one\n")
+	}
+	writeFile(t, filepath.Join(dir, "hello", "main.go"), helloProgram("two"))
+	if got := goRunHello(t, app); got != "two\n" {
+		t.Errorf("stale binary after the source change: got output %q, want %q", got, "two\n")
+	}
+}
+
+func TestGoRun_Workspace(t *testing.T) {
+	setupGoToolCache(t)
+	t.Setenv("GOWORK", "")
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app")
+	writeFile(t, filepath.Join(dir, "go.work"), "go 1.21\n\nuse (\n\t./app\n\t./hello\n)\n")
+	writeFile(t, filepath.Join(app, "go.mod"), "module example.com/app\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "hello", "go.mod"), "module example.com/hello\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "hello", "main.go"), helloProgram("one"))
+
+	if got := goRunHello(t, app); got != "one\n" {
+		t.Fatalf("got output %q, want %q", got, "one\n")
+	}
+	writeFile(t, filepath.Join(dir, "hello", "main.go"), helloProgram("two"))
+	if got := goRunHello(t, app); got != "two\n" {
+		t.Errorf("stale binary after the source change: got output %q, want %q", got, "two\n")
+	}
+}
+
+// goRunHello runs example.com/hello in the directory and returns its output.
+func goRunHello(t *testing.T, dir string) string {
+	t.Helper()
+	f := &goyek.Flow{}
+	var output, log strings.Builder
+	f.SetOutput(&log)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			GoRun(a, "example.com/hello", Dir(dir), Stdout(&output))
+		},
+	})
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatalf("%v: %s", err, log.String())
+	}
+	return output.String()
+}
+
+func TestHasLocalReplace(t *testing.T) {
+	tests := []struct {
+		mod  string
+		want bool
+	}{
+		{mod: "module a\n", want: false},
+		{mod: "module a\nreplace b => c v1.0.0\n", want: false},
+		{mod: "module a\nreplace b => ./b\n", want: true},
+		{mod: "module a\nreplace b v1.0.0 => ../b // local\n", want: true},
+		{mod: "module a\nreplace (\n\tb => c v1.0.0\n\td => /src/d\n)\n", want: true},
+		{mod: "module a\nreplace (\n\tb => c v1.0.0\n)\nrequire d v1.0.0\n", want: false},
+		{mod: "module a\n// replace b => ./b\n", want: false},
+	}
+	for _, tc := range tests {
+		if got := hasLocalReplace([]byte(tc.mod)); got != tc.want {
+			t.Errorf("hasLocalReplace(%q) = %v, want %v", tc.mod, got, tc.want)
+		}
+	}
+}
+
+func helloProgram(msg string) string {
+	return "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"" + msg + "\") }\n"
+}
+
+func TestIsCanonicalVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "v0.30.0", want: true},
+		{version: "v2.1.0-rc.1", want: true},
+		{version: "v0.0.0-20250101000000-abcdefabcdef", want: true},
+		{version: "v2.0.0+incompatible", want: true},
+		{version: "latest", want: false},
+		{version: "master", want: false},
+		{version: "v1", want: false},
+		{version: "v1.2", want: false},
+		{version: ">=v1.2.0", want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			if got := isCanonicalVersion(tc.version); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGoTool_Local(t *testing.T) {
+	setupGoToolCache(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/tools\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "x", "main.go"), "package main\n\nfunc main() {}\n")
+
+	f := &goyek.Flow{}
+	var bin string
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			bin, _ = GoTool(a, "./x", Dir(dir))
+		},
+	})
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := filepath.Base(bin); !strings.HasPrefix(got, "x") {
+		t.Errorf("got binary name %q, want x", got)
+	}
+}
+
+func TestGoTool_OnlyDirAndEnv(t *testing.T) {
+	setupGoToolCache(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/tools\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "x", "main.go"), "package main\n\nfunc main() {}\n")
+	var output strings.Builder
+	wrap := func(_ *goyek.A, cmd *exec.Cmd) {
+		cmd.Path = filepath.Join(dir, "missing")
+		cmd.Stdout = &output
+	}
+
+	f := &goyek.Flow{}
+	var ok bool
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			_, ok = GoTool(a, "./x", Dir(dir), Env("CGO_ENABLED", "0"), wrap)
+		},
+	})
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok {
+		t.Error("GoTool should build using only the directory and the environment")
+	}
+	if output.Len() > 0 {
+		t.Errorf("the build wrote to the command output: %q", output.String())
+	}
+}
+
+func TestGoRun_BuildError(t *testing.T) {
+	setupGoToolCache(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/broken\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() { undefined() }\n")
+
+	f := &goyek.Flow{}
+	f.SetOutput(&strings.Builder{})
+	var ok bool
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			ok = GoRun(a, ".", Dir(dir))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err == nil {
+		t.Error("expected the flow to fail")
+	}
+	if ok {
+		t.Error("GoRun returned true for a build error")
+	}
+}
+
+func TestBinName(t *testing.T) {
+	tests := []struct {
+		pkg  string
+		want string
+	}{
+		{pkg: "golang.org/x/tools/cmd/stringer@v0.30.0", want: "stringer"},
+		{pkg: "github.com/golangci/golangci-lint/v2/cmd/golangci-lint", want: "golangci-lint"},
+		{pkg: "example.com/foo/v2@latest", want: "foo"},
+		{pkg: "example.com/v1", want: "v1"},
+		{pkg: "./tools/x", want: "x"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.pkg, func(t *testing.T) {
+			got := strings.TrimSuffix(binName(tc.pkg, "."), ".exe")
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// setupGoToolCache uses a temporary cache directory for the built binaries
+// while keeping the Go build cache to avoid rebuilding the standard library.
+func setupGoToolCache(t *testing.T) {
+	t.Helper()
+	out, err := exec.Command("go", "env", "GOCACHE").Output()
+	if err != nil {
+		t.Skip("go command not available: ", err)
+	}
+	t.Setenv("GOCACHE", strings.TrimSpace(string(out)))
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOWORK", "off")
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+}
+
+func writeFile(t *testing.T, name, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
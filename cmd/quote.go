@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Quote returns the argument quoted so that parsing the command line
+// in Exec gives back the original argument.
+// The argument must be a valid UTF-8 string.
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsFunc(s, isUnsafe) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Join quotes the arguments using Quote and joins them with spaces.
+// Note that Exec treats leading arguments like NAME=value
+// as environment variables.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ExecArgs runs the program with the arguments like Exec
+// but without parsing a command line.
+// The first element is the program to run.
+// It calls a.Error[f] and returns false in case of any problems.
+// Example usage:
+//
+//	cmd.ExecArgs(a, []string{"gofmt", "-l", fileName}, cmd.Dir("pkg"))
+func ExecArgs(a *goyek.A, args []string, opts ...Option) bool {
+	a.Helper()
+
+	if len(args) == 0 {
+		panic("no command provided")
+	}
+
+	cmd := command(a, args[0], args[1:], nil, opts)
+	return run(a, cmd)
+}
+
+// isUnsafe reports whether the character needs quoting.
+func isUnsafe(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("_-+=:,./@%", r)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/mattn/go-shellwords"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: "''"},
+		{in: "file.go", want: "file.go"},
+		{in: "--flag=a/b:c,d@e%f+g", want: "--flag=a/b:c,d@e%f+g"},
+		{in: "with space", want: "'with space'"},
+		{in: "it's", want: `'it'\''s'`},
+		{in: `"$HOME"`, want: `'"$HOME"'`},
+		{in: "a;b", want: "'a;b'"},
+		{in: `back\slash`, want: `'back\slash'`},
+		{in: "zażółć", want: "'zażółć'"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := Quote(tc.in); got != tc.want {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJoin_RoundTrip(t *testing.T) {
+	roundTrip := func(args []string) bool {
+		got, err := shellwords.Parse(Join(args))
+		if err != nil {
+			t.Logf("parse %q: %v", Join(args), err)
+			return false
+		}
+		if len(args) == 0 {
+			return len(got) == 0
+		}
+		return reflect.DeepEqual(got, args)
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+
+	special := []string{"", " ", "'", `"`, `\`, `\n`, `\t`, "\n", "\t", "\r", "$HOME", "`ls`", "$(ls)", "(", ")", ";", "&&", "|", "<", ">", "2>", "*", "~", "#", "a=b"}
+	if !roundTrip(special) {
+		t.Errorf("special characters do not round trip: %q", Join(special))
+	}
+}
+
+func TestExecArgs(t *testing.T) {
+	f := &goyek.Flow{}
+	var output strings.Builder
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			ExecArgs(a, []string{"echo", "it's", "$HOME", "a  b"}, Stdout(&output))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := output.String(), "it's $HOME a  b\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExecArgs_NoCommand(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("ExecArgs did not panic when no command was provided")
+		}
+	}()
+	ExecArgs(&goyek.A{}, nil)
+}
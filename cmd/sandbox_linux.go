@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/goyek/goyek/v3"
+	"golang.org/x/sys/unix"
+)
+
+// sandboxArg0 is the name of the process re-executed to apply the sandbox
+// before running the command.
+const sandboxArg0 = "goyek-x-cmd-sandbox"
+
+// sandboxExitCode is the exit code when the sandbox cannot be applied.
+const sandboxExitCode = 126
+
+type sandboxConfig struct {
+	Path     string   `json:"path"`
+	Writable []string `json:"writable"`
+}
+
+func init() {
+	// The re-executed process is:
+	// goyek-x-cmd-sandbox <config> <command args...>
+	const minArgs = 3
+	if len(os.Args) < minArgs || os.Args[0] != sandboxArg0 {
+		return
+	}
+	if err := sandboxExec(os.Args[1], os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox:", err)
+		os.Exit(sandboxExitCode)
+	}
+}
+
+// Sandbox is an option to run the command with restricted access (Linux only).
+// The command can write only to the given paths and the null device,
+// and it has no network access. Reading files is not restricted.
+// Relative paths are resolved against the working directory of the command.
+//
+// The file system restrictions use Landlock (Linux 5.13 or newer)
+// and the network is isolated using a network namespace
+// within an unprivileged user namespace.
+// The command fails to start when the kernel does not support them.
+// With Landlock ABI versions older than 3 (Linux 6.2), the command
+// can still truncate existing files outside the given paths.
+//
+// The program re-executes itself to apply the restrictions
+// before running the command.
+func Sandbox(writable ...string) Option {
+	return func(_ *goyek.A, cmd *exec.Cmd) {
+		if cmd.Err != nil {
+			return
+		}
+		if _, err := landlockABI(); err != nil {
+			cmd.Err = fmt.Errorf("sandbox: landlock is not supported by the kernel: %w", err)
+			return
+		}
+		self, err := os.Executable()
+		if err != nil {
+			cmd.Err = fmt.Errorf("sandbox: %w", err)
+			return
+		}
+		cfg, err := json.Marshal(sandboxConfig{Path: cmd.Path, Writable: writable})
+		if err != nil {
+			cmd.Err = fmt.Errorf("sandbox: %w", err)
+			return
+		}
+
+		cmd.Args = append([]string{sandboxArg0, string(cfg)}, cmd.Args...)
+		cmd.Path = self
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET,
+			UidMappings: []syscall.SysProcIDMap{
+				{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1},
+			},
+			GidMappings: []syscall.SysProcIDMap{
+				{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1},
+			},
+		}
+	}
+}
+
+// sandboxExec restricts the file system access and executes the command.
+func sandboxExec(config string, args []string) error {
+	var cfg sandboxConfig
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return err
+	}
+
+	// Landlock restricts only the calling thread which then executes the command.
+	runtime.LockOSThread()
+	if err := landlockRestrict(append(cfg.Writable, os.DevNull)); err != nil {
+		return err
+	}
+	return syscall.Exec(cfg.Path, args, os.Environ()) //nolint:gosec // executing the command is intended
+}
+
+// landlockABI returns the Landlock ABI version supported by the kernel.
+func landlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(abi), nil
+}
+
+// landlockRestrict allows writing only to the given paths.
+func landlockRestrict(writable []string) error {
+	abi, err := landlockABI()
+	if err != nil {
+		return fmt.Errorf("landlock is not supported by the kernel: %w", err)
+	}
+	var handled uint64 = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE | unix.LANDLOCK_ACCESS_FS_MAKE_CHAR | unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG | unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK | unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+	const (
+		abiRefer    = 2
+		abiTruncate = 3
+	)
+	if abi >= abiRefer {
+		handled |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= abiTruncate {
+		handled |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), //nolint:gosec // the system call requires a pointer to the attributes
+		unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("create ruleset: %w", errno)
+	}
+	rulesetFd := int(fd)
+	defer unix.Close(rulesetFd) //nolint:errcheck // closing a read-only descriptor
+
+	for _, path := range writable {
+		if err := landlockAllow(rulesetFd, path, handled); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("set no new privileges: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return fmt.Errorf("restrict self: %w", errno)
+	}
+	return nil
+}
+
+// landlockAllow adds the rule allowing the access beneath the path.
+func landlockAllow(rulesetFd int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	defer unix.Close(fd) //nolint:errcheck // closing a path descriptor
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return &os.PathError{Op: "stat", Path: path, Err: err}
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		// Only file related access rights can be used for files.
+		access &= unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)} //nolint:gosec // file descriptors fit in int32
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd), unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&attr)), //nolint:gosec // the system call requires a pointer to the attributes
+		0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("allow %s: %w", path, errno)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+)
+
+func TestSandbox(t *testing.T) {
+	skipWithoutSandbox(t)
+	writable := t.TempDir()
+	readOnly := t.TempDir()
+
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	var writeOK, writeDenied bool
+	var netDev strings.Builder
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			writeOK = Exec(a, "sh -c 'echo ok > allowed.txt'", Dir(writable), Sandbox("."))
+			writeDenied = Exec(a, "sh -c 'echo denied > "+filepath.Join(readOnly, "denied.txt")+"'", Sandbox(writable))
+			Exec(a, "cat /proc/net/dev", Sandbox(), Stdout(&netDev))
+		},
+	})
+
+	_ = f.Execute(context.Background(), []string{"test"})
+
+	if !writeOK {
+		t.Errorf("write to an allowed path failed: %s", out.String())
+	}
+	if writeDenied {
+		t.Error("write to a not allowed path succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(readOnly, "denied.txt")); err == nil {
+		t.Error("file created in a not allowed path")
+	}
+	for _, line := range strings.Split(netDev.String(), "\n") {
+		name, _, found := strings.Cut(strings.TrimSpace(line), ":")
+		if found && name != "lo" {
+			t.Errorf("network interface %q available in the sandbox", name)
+		}
+	}
+}
+
+func TestSandbox_MissingPath(t *testing.T) {
+	skipWithoutSandbox(t)
+
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Exec(a, "true", Sandbox(filepath.Join(t.TempDir(), "missing")))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err == nil {
+		t.Fatal("expected the flow to fail")
+	}
+	if got := out.String(); !strings.Contains(got, "sandbox: open") {
+		t.Errorf("output %q does not contain the sandbox error", got)
+	}
+}
+
+// skipWithoutSandbox skips the test when the kernel does not support the sandbox.
+func skipWithoutSandbox(t *testing.T) {
+	t.Helper()
+	if _, err := landlockABI(); err != nil {
+		t.Skip("landlock not supported: ", err)
+	}
+	f := &goyek.Flow{}
+	f.SetOutput(&strings.Builder{})
+	var ok bool
+	f.Define(goyek.Task{
+		Name: "probe",
+		Action: func(a *goyek.A) {
+			ok = Exec(a, "true", Sandbox())
+		},
+	})
+	_ = f.Execute(context.Background(), []string{"probe"})
+	if !ok {
+		t.Skip("user namespaces not available")
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package cmd
+
+import (
+	"errors"
+	"os/exec"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// Sandbox is an option to run the command with restricted access (Linux only).
+// The command can write only to the given paths and the null device,
+// and it has no network access.
+//
+// On this platform the command always fails to start.
+func Sandbox(...string) Option {
+	return func(_ *goyek.A, cmd *exec.Cmd) {
+		cmd.Err = errors.New("sandbox: not supported on this platform")
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/mattn/go-isatty"
+)
+
+var noStdin atomic.Bool
+
+// Intervals used when detecting commands blocked reading the standard input.
+var (
+	stdinWarnAfter    = 10 * time.Second
+	stdinPollInterval = time.Second
+)
+
+func init() {
+	noStdin.Store(os.Getenv("CI") != "")
+}
+
+// NoStdin prevents commands from reading the standard input of the process.
+// Instead, the commands read from the null device
+// unless the Stdin, StdinString or StdinFile option is used.
+//
+// It is enabled by default when the CI environment variable is set
+// to a non-empty string so that commands that unexpectedly prompt
+// for input do not hang the pipeline.
+func NoStdin() {
+	noStdin.Store(true)
+}
+
+// StdinString is an option to set the standard input to the string.
+func StdinString(s string) Option {
+	return func(_ *goyek.A, cmd *exec.Cmd) {
+		cmd.Stdin = strings.NewReader(s)
+	}
+}
+
+// StdinFile is an option to set the standard input to the named file.
+// The file is closed when the task finishes.
+func StdinFile(name string) Option {
+	return func(a *goyek.A, cmd *exec.Cmd) {
+		f, err := os.Open(name) //nolint:gosec // reading the file provided by the user is intended
+		if err != nil {
+			cmd.Err = fmt.Errorf("open stdin file: %w", err)
+			return
+		}
+		a.Cleanup(func() {
+			_ = f.Close()
+		})
+		cmd.Stdin = f
+	}
+}
+
+// defaultStdin returns the standard input used for commands
+// when no option sets it.
+func defaultStdin() *os.File {
+	if noStdin.Load() {
+		return nil
+	}
+	return os.Stdin
+}
+
+// watchStdin warns when the started command seems to be blocked
+// reading its standard input. The returned function stops watching.
+//
+// Terminals are not watched as reading them is expected,
+// for example when the command prompts for a password.
+// Only the started process is inspected, so commands started
+// by it, like the ones run using sh -c, are not detected.
+func watchStdin(a *goyek.A, cmd *exec.Cmd) func() {
+	if !stdinWatchSupported || cmd.Stdin == nil || cmd.Process == nil {
+		// The detection is not supported or the null device is used,
+		// which never blocks.
+		return func() {}
+	}
+	if f, ok := cmd.Stdin.(interface{ Fd() uintptr }); ok &&
+		(isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())) {
+		return func() {}
+	}
+	pid := cmd.Process.Pid
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(stdinPollInterval)
+		defer ticker.Stop()
+		var blockedSince time.Time
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if !readingStdin(pid) {
+					blockedSince = time.Time{}
+					continue
+				}
+				if blockedSince.IsZero() {
+					blockedSince = now
+				}
+				if now.Sub(blockedSince) >= stdinWarnAfter {
+					a.Logf("warning: process %d seems to be waiting for the standard input", pid)
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// stdinWatchSupported reports whether readingStdin is supported.
+const stdinWatchSupported = true
+
+// readingStdin reports whether any thread of the process
+// is blocked in the read system call on the standard input.
+func readingStdin(pid int) bool {
+	tasks, err := filepath.Glob(filepath.Join("/proc", strconv.Itoa(pid), "task", "*", "syscall"))
+	if err != nil {
+		return false
+	}
+	for _, task := range tasks {
+		data, err := os.ReadFile(task) //nolint:gosec // the path is built from the process ID
+		if err != nil {
+			continue
+		}
+		// The format is: syscall number followed by its arguments in hex.
+		fields := strings.Fields(string(data))
+		const minFields = 2
+		if len(fields) < minFields {
+			continue
+		}
+		nr, err := strconv.Atoi(fields[0])
+		if err != nil || nr != syscall.SYS_READ {
+			continue
+		}
+		if fd, err := strconv.ParseUint(strings.TrimPrefix(fields[1], "0x"), 16, 64); err == nil && fd == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/goyek/goyek/v3"
+)
+
+func TestExec_WarnBlockedStdin(t *testing.T) {
+	shortStdinWarn(t)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	timer := time.AfterFunc(time.Second, func() { _ = w.Close() })
+	defer timer.Stop()
+
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Exec(a, "cat", Stdin(r))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out.String(); !strings.Contains(got, "seems to be waiting for the standard input") {
+		if _, err := os.ReadFile("/proc/self/syscall"); err != nil {
+			t.Skip("system call information not available: ", err)
+		}
+		t.Errorf("output %q does not contain the warning", got)
+	}
+}
+
+func TestExec_TerminalStdinNotWatched(t *testing.T) {
+	shortStdinWarn(t)
+	master, tty := openPty(t)
+	// The end-of-file character makes cat exit after it was waiting for a while.
+	timer := time.AfterFunc(300*time.Millisecond, func() { _, _ = master.Write([]byte{4}) })
+	defer timer.Stop()
+
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Exec(a, "cat", Stdin(tty))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out.String(); strings.Contains(got, "seems to be waiting") {
+		t.Errorf("output %q should not contain the warning for a terminal", got)
+	}
+}
+
+func shortStdinWarn(t *testing.T) {
+	t.Helper()
+	oldWarnAfter, oldPollInterval := stdinWarnAfter, stdinPollInterval
+	stdinWarnAfter, stdinPollInterval = 50*time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() {
+		stdinWarnAfter, stdinPollInterval = oldWarnAfter, oldPollInterval
+	})
+}
+
+// openPty returns the master and the slave of a new pseudo terminal.
+func openPty(t *testing.T) (*os.File, *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skip("pseudo terminals not available: ", err)
+	}
+	t.Cleanup(func() { _ = master.Close() })
+	var unlock int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 { //nolint:gosec // ioctl argument
+		t.Skip("unlock pseudo terminal: ", errno)
+	}
+	var n uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 { //nolint:gosec // ioctl argument
+		t.Skip("get pseudo terminal number: ", errno)
+	}
+	tty, err := os.OpenFile(filepath.Join("/dev/pts", strconv.Itoa(int(n))), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skip("open pseudo terminal: ", err)
+	}
+	t.Cleanup(func() { _ = tty.Close() })
+	return master, tty
+}
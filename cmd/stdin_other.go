@@ -0,0 +1,12 @@
+//go:build !linux
+
+package cmd
+
+// stdinWatchSupported reports whether readingStdin is supported.
+const stdinWatchSupported = false
+
+// readingStdin reports whether the process is blocked reading
+// the standard input. The detection is supported only on Linux.
+func readingStdin(int) bool {
+	return false
+}
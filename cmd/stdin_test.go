@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+)
+
+func TestStdinString(t *testing.T) {
+	f := &goyek.Flow{}
+	var output strings.Builder
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Exec(a, "cat", StdinString("hello"), Stdout(&output))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := output.String(); got != "hello" {
+		t.Errorf("got %q, want %q", got, "hello")
+	}
+}
+
+func TestStdinFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(name, []byte("from file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &goyek.Flow{}
+	var output strings.Builder
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			Exec(a, "cat", StdinFile(name), Stdout(&output))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := output.String(); got != "from file" {
+		t.Errorf("got %q, want %q", got, "from file")
+	}
+}
+
+func TestStdinFile_NotExist(t *testing.T) {
+	f := &goyek.Flow{}
+	out := &strings.Builder{}
+	f.SetOutput(out)
+	var ok bool
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			ok = Exec(a, "cat", StdinFile(filepath.Join(t.TempDir(), "missing.txt")))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err == nil {
+		t.Error("expected the flow to fail")
+	}
+	if ok {
+		t.Error("Exec returned true for a missing stdin file")
+	}
+	if got := out.String(); !strings.Contains(got, "open stdin file") {
+		t.Errorf("output %q does not contain the error", got)
+	}
+}
+
+func TestNoStdin(t *testing.T) {
+	old := noStdin.Load()
+	t.Cleanup(func() { noStdin.Store(old) })
+	NoStdin()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	oldStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = oldStdin })
+
+	f := &goyek.Flow{}
+	var output strings.Builder
+	f.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			// cat would block forever reading the never closed pipe.
+			Exec(a, "cat", Stdout(&output))
+		},
+	})
+
+	if err := f.Execute(context.Background(), []string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := output.String(); got != "" {
+		t.Errorf("got %q, want empty output", got)
+	}
+}
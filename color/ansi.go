@@ -0,0 +1,12 @@
+package color
+
+import "regexp"
+
+// ansiEscape matches ANSI escape sequences: CSI sequences like SGR,
+// OSC sequences like hyperlinks, and other two-character sequences.
+var ansiEscape = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-_])`)
+
+// stripANSI removes the ANSI escape sequences from the text.
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
@@ -0,0 +1,27 @@
+package color
+
+import (
+	"os"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// ReportCI is a runner middleware, which reports the tasks in a way
+// native to the CI system the flow runs in.
+// It uses [ReportGitHub] in GitHub Actions, [ReportGitLab] in GitLab CI,
+// and [ReportTeamCity] in TeamCity. Otherwise, it does nothing.
+//
+// The CI system is detected using the GITHUB_ACTIONS, GITLAB_CI,
+// and TEAMCITY_VERSION environment variables.
+func ReportCI(next goyek.Runner) goyek.Runner {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return ReportGitHub(next)
+	case os.Getenv("GITLAB_CI") == "true":
+		return ReportGitLab(next)
+	case os.Getenv("TEAMCITY_VERSION") != "":
+		return ReportTeamCity(next)
+	default:
+		return next
+	}
+}
@@ -0,0 +1,39 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestReportCI(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantPrefix string
+	}{
+		{name: "none", wantPrefix: ""},
+		{name: "github", env: map[string]string{"GITHUB_ACTIONS": "true"}, wantPrefix: "::group::task\n"},
+		{name: "gitlab", env: map[string]string{"GITLAB_CI": "true"}, wantPrefix: "\x1b[0Ksection_start:"},
+		{name: "teamcity", env: map[string]string{"TEAMCITY_VERSION": "2025.07"}, wantPrefix: "##teamcity[blockOpened name='task'"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "TEAMCITY_VERSION"} {
+				t.Setenv(key, tc.env[key])
+			}
+			out := &strings.Builder{}
+			runner := goyekcolor.ReportCI(func(goyek.Input) goyek.Result { return goyek.Result{Status: goyek.StatusPassed} })
+
+			runner(goyek.Input{Output: out, TaskName: "task"})
+
+			got := out.String()
+			if !strings.HasPrefix(got, tc.wantPrefix) || (tc.wantPrefix == "") != (got == "") {
+				t.Errorf("got output %q, want prefix %q", got, tc.wantPrefix)
+			}
+		})
+	}
+}
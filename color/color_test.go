@@ -1,10 +1,6 @@
 package color_test
 
-import (
-	"testing"
-
-	fatihcolor "github.com/fatih/color"
-)
+import "testing"
 
 const (
 	ansiBlue   = "\x1b[34m"
@@ -17,9 +13,5 @@ const (
 func forceColor(t *testing.T) {
 	t.Helper()
 	t.Setenv("NO_COLOR", "")
-	oldNoColor := fatihcolor.NoColor
-	fatihcolor.NoColor = false
-	t.Cleanup(func() {
-		fatihcolor.NoColor = oldNoColor
-	})
+	t.Setenv("FORCE_COLOR", "1")
 }
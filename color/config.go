@@ -0,0 +1,203 @@
+package color
+
+import "time"
+
+// Option configures any feature of the package which colors the output.
+// Options specific to some features have their own types,
+// like [LoggerOption], so that they cannot be given to other features.
+type Option interface {
+	LoggerOption
+	StackOption
+	HeartbeatOption
+	apply(*config)
+}
+
+// LoggerOption configures [CodeLineLogger] and [GitHubLogger].
+type LoggerOption interface {
+	applyLogger(*config)
+}
+
+// StatusOption configures [NewReportStatus].
+type StatusOption interface {
+	applyStatus(*config)
+}
+
+// StackOption configures [FormatStack] and the stacks
+// printed by [NewReportStatus].
+type StackOption interface {
+	StatusOption
+	applyStack(*config)
+}
+
+// HeartbeatOption configures [ReportHeartbeat].
+type HeartbeatOption interface {
+	applyHeartbeat(*config)
+}
+
+// HTMLOption configures [HTMLWriter].
+type HTMLOption interface {
+	applyHTML(*config)
+}
+
+// LinksOption configures the hyperlinks printed by the loggers
+// and allowed by [HTMLWriter].
+type LinksOption interface {
+	LoggerOption
+	HTMLOption
+}
+
+type optionFunc func(*config)
+
+func (fn optionFunc) apply(cfg *config)          { fn(cfg) }
+func (fn optionFunc) applyLogger(cfg *config)    { fn(cfg) }
+func (fn optionFunc) applyStatus(cfg *config)    { fn(cfg) }
+func (fn optionFunc) applyStack(cfg *config)     { fn(cfg) }
+func (fn optionFunc) applyHeartbeat(cfg *config) { fn(cfg) }
+
+type loggerOptionFunc func(*config)
+
+func (fn loggerOptionFunc) applyLogger(cfg *config) { fn(cfg) }
+
+type statusOptionFunc func(*config)
+
+func (fn statusOptionFunc) applyStatus(cfg *config) { fn(cfg) }
+
+type stackOptionFunc func(*config)
+
+func (fn stackOptionFunc) applyStatus(cfg *config) { fn(cfg) }
+func (fn stackOptionFunc) applyStack(cfg *config)  { fn(cfg) }
+
+type heartbeatOptionFunc func(*config)
+
+func (fn heartbeatOptionFunc) applyHeartbeat(cfg *config) { fn(cfg) }
+
+type linksOptionFunc func(*config)
+
+func (fn linksOptionFunc) applyLogger(cfg *config) { fn(cfg) }
+func (fn linksOptionFunc) applyHTML(cfg *config)   { fn(cfg) }
+
+type config struct {
+	Theme         Theme
+	PathMode      PathMode
+	Links         string
+	Timestamp     string
+	Elapsed       bool
+	SourceContext int
+	CollapseStack bool
+	StatusFormat  *StatusFormat
+
+	HeartbeatWarn     time.Duration
+	HeartbeatCritical time.Duration
+}
+
+// newConfig returns the default configuration changed by the options.
+// The apply function is the method of the option type, like LoggerOption.applyLogger.
+func newConfig[O any](opts []O, apply func(O, *config)) *config {
+	c := &config{
+		Theme:        themeFromEnv(),
+		StatusFormat: defaultStatusFormat,
+	}
+	for _, opt := range opts {
+		apply(opt, c)
+	}
+	return c
+}
+
+// WithTheme specifies the theme to use.
+// If none is specified, the theme is selected using the GOYEK_COLOR_THEME
+// environment variable which can be set to "default", "high-contrast",
+// or "colorblind".
+func WithTheme(theme Theme) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.Theme = theme
+	})
+}
+
+// WithPathMode specifies how the logger prints the file path of the call site.
+// If none is specified, [PathBase] is used.
+func WithPathMode(mode PathMode) LoggerOption {
+	return loggerOptionFunc(func(cfg *config) {
+		cfg.PathMode = mode
+	})
+}
+
+// WithLinks makes the logger print the file and line of the call site
+// as an OSC 8 hyperlink, which supporting terminals make clickable.
+// The links are printed only if the output is colorized.
+//
+// The template "file" creates file URLs. Otherwise, {path} and {line}
+// in the template are replaced with the absolute file path, having each
+// element escaped for use in a URL, and the line number,
+// for example "vscode://file/{path}:{line}".
+//
+// Given to [NewHTMLWriter], it allows the links using the scheme
+// of the template, like "vscode", in the page.
+func WithLinks(template string) LinksOption {
+	return linksOptionFunc(func(cfg *config) {
+		cfg.Links = template
+	})
+}
+
+// DefaultTimestampLayout is the time layout used by [WithTimestamp]
+// when an empty layout is given.
+const DefaultTimestampLayout = "15:04:05.000"
+
+// WithTimestamp makes the logger prefix each record with the wall-clock time
+// formatted using the layout, see [time.Layout].
+// If the layout is empty, [DefaultTimestampLayout] is used.
+func WithTimestamp(layout string) LoggerOption {
+	if layout == "" {
+		layout = DefaultTimestampLayout
+	}
+	return loggerOptionFunc(func(cfg *config) {
+		cfg.Timestamp = layout
+	})
+}
+
+// WithElapsed makes the logger prefix each record with the time elapsed
+// since the start of the task, see [CodeLineLogger.Middleware].
+func WithElapsed() LoggerOption {
+	return loggerOptionFunc(func(cfg *config) {
+		cfg.Elapsed = true
+	})
+}
+
+// WithSourceContext makes the logger include the source code around
+// the call site in the error and fatal records. The given number of lines
+// is printed before and after the line of the call site.
+// The source code is printed only if the file is available.
+func WithSourceContext(lines int) LoggerOption {
+	return loggerOptionFunc(func(cfg *config) {
+		cfg.SourceContext = lines
+	})
+}
+
+// WithCollapsedStack makes the panic stacks printed by [NewReportStatus]
+// and [FormatStack] collapse the consecutive repeated frames,
+// which occur for example in case of infinite recursion.
+func WithCollapsedStack() StackOption {
+	return stackOptionFunc(func(cfg *config) {
+		cfg.CollapseStack = true
+	})
+}
+
+// WithStatusFormat specifies the format of the records written
+// by [NewReportStatus] when a task starts and ends.
+// Use [ParseStatusFormat] to create it.
+func WithStatusFormat(format *StatusFormat) StatusOption {
+	return statusOptionFunc(func(cfg *config) {
+		if format != nil {
+			cfg.StatusFormat = format
+		}
+	})
+}
+
+// WithHeartbeatThresholds specifies the task durations after which
+// the lines printed by [ReportHeartbeat] use the Warn and Failed styles
+// of the theme. A non-positive threshold disables the escalation.
+func WithHeartbeatThresholds(warn, critical time.Duration) HeartbeatOption {
+	return heartbeatOptionFunc(func(cfg *config) {
+		cfg.HeartbeatWarn = warn
+		cfg.HeartbeatCritical = critical
+	})
+}
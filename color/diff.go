@@ -0,0 +1,73 @@
+package color
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
+
+	"github.com/goyek/x/internal/diff"
+)
+
+// Diff logs the line-based unified diff of the texts using a.Log
+// and returns true if the texts are equal.
+// [CodeLineLogger] colors removed lines like failures and added lines
+// like passes using its theme if the output is colorized, see [Enabled].
+// Other loggers get the plain text.
+// It does not fail the task. Example usage:
+//
+//	if !color.Diff(a, string(golden), got) {
+//		a.Error("output does not match the golden file")
+//	}
+func Diff(a *goyek.A, want, got string) bool {
+	a.Helper()
+	text := diff.Unified("want", "got", want, got)
+	if text == "" {
+		return true
+	}
+	a.Log(diffRecord(text))
+	return false
+}
+
+// diffRecord is a unified diff logged by Diff.
+type diffRecord string
+
+func (r diffRecord) String() string {
+	return string(r)
+}
+
+func (r diffRecord) colorize(theme Theme, colored bool) string {
+	return colorDiff(string(r), theme, colored)
+}
+
+// colorDiff colorizes the lines of the unified diff.
+func colorDiff(text string, theme Theme, colored bool) string {
+	if !colored {
+		return text
+	}
+	header := NewStyle(color.Bold)
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		switch {
+		case i < 2: // the file names
+			lines[i] = colorLine(header, line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = colorLine(theme.Task, line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = colorLine(theme.Failed, line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = colorLine(theme.Passed, line)
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// colorLine colorizes the line without its trailing newline.
+func colorLine(style Style, line string) string {
+	text, ok := strings.CutSuffix(line, "\n")
+	text = style.sprint(true, text)
+	if ok {
+		text += "\n"
+	}
+	return text
+}
@@ -0,0 +1,125 @@
+package color_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fatihcolor "github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		color  bool
+		want   string
+		got    string
+		equal  bool
+		output string
+	}{
+		{
+			name:   "equal",
+			want:   "a\nb\n",
+			got:    "a\nb\n",
+			equal:  true,
+			output: "",
+		},
+		{
+			name:  "plain",
+			want:  "a\nb\n",
+			got:   "a\nc\n",
+			equal: false,
+			output: "      diff_test.go:69: --- want\n" +
+				"          +++ got\n" +
+				"          @@ -1,2 +1,2 @@\n" +
+				"           a\n" +
+				"          -b\n" +
+				"          +c\n",
+		},
+		{
+			name:  "colored",
+			color: true,
+			want:  "a\nb\n",
+			got:   "a\nc\n",
+			equal: false,
+			output: "      diff_test.go:69: \x1b[1m--- want\x1b[22m\n" +
+				"          \x1b[1m+++ got\x1b[22m\n" +
+				"          " + ansiBlue + "@@ -1,2 +1,2 @@" + ansiReset + "\n" +
+				"           a\n" +
+				"          " + ansiRed + "-b" + ansiReset + "\n" +
+				"          " + ansiGreen + "+c" + ansiReset + "\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.color {
+				forceColor(t)
+			}
+			var equal bool
+			flow := &goyek.Flow{}
+			out := &strings.Builder{}
+			flow.SetOutput(out)
+			flow.SetLogger(&goyekcolor.CodeLineLogger{})
+			flow.Define(goyek.Task{
+				Name: "task",
+				Action: func(a *goyek.A) {
+					equal = goyekcolor.Diff(a, tc.want, tc.got)
+				},
+			})
+
+			if err := flow.Execute(context.Background(), []string{"task"}); err != nil {
+				t.Fatalf("flow failed: %v", err)
+			}
+
+			if equal != tc.equal {
+				t.Errorf("got %v, want %v", equal, tc.equal)
+			}
+			if got := out.String(); got != tc.output {
+				t.Errorf("got output %q, want %q", got, tc.output)
+			}
+		})
+	}
+}
+
+func TestDiffWithTheme(t *testing.T) {
+	forceColor(t)
+	theme := goyekcolor.DefaultTheme()
+	theme.Failed = goyekcolor.NewStyle(fatihcolor.FgMagenta)
+
+	got := logDiff(t, goyekcolor.NewCodeLineLogger(goyekcolor.WithTheme(theme)))
+
+	if want := "\x1b[35m-b" + ansiReset + "\n"; !strings.Contains(got, want) {
+		t.Errorf("output %q does not contain %q", got, want)
+	}
+}
+
+func TestDiffJSONLogger(t *testing.T) {
+	forceColor(t)
+
+	got := logDiff(t, &goyekcolor.JSONLogger{})
+
+	if want := `"msg":"--- want\n+++ got\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"}`; !strings.Contains(got, want) {
+		t.Errorf("output %q does not contain %q", got, want)
+	}
+}
+
+func logDiff(t *testing.T, logger goyek.Logger) string {
+	t.Helper()
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(logger)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			goyekcolor.Diff(a, "a\nb\n", "a\nc\n")
+		},
+	})
+	if err := flow.Execute(context.Background(), []string{"task"}); err != nil {
+		t.Fatalf("flow failed: %v", err)
+	}
+	return out.String()
+}
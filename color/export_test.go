@@ -0,0 +1,12 @@
+package color
+
+import "io"
+
+// SetTerminal overrides the terminal detection of the Progress.
+// The width is used if the output is a terminal.
+func (p *Progress) SetTerminal(terminal bool, width int) {
+	p.terminal = func(io.Writer) (int, bool) { return width, terminal }
+}
+
+// Hyperlink is hyperlink exported for tests.
+var Hyperlink = hyperlink
@@ -0,0 +1,128 @@
+package color
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// GitHubLogger decorates the log like [CodeLineLogger], except that
+// errors and skips are written only as GitHub Actions workflow commands,
+// so that they are shown as annotations of the workflow run
+// and in the log. Errors are reported using the error command
+// and skips are reported using the warning command.
+//
+// The file paths are relative to the GITHUB_WORKSPACE directory.
+//
+// The zero value is ready to use with the default configuration.
+type GitHubLogger struct {
+	CodeLineLogger
+}
+
+// NewGitHubLogger returns a GitHubLogger configured using the options.
+func NewGitHubLogger(opts ...LoggerOption) *GitHubLogger {
+	return &GitHubLogger{CodeLineLogger: CodeLineLogger{cfg: newConfig(opts, LoggerOption.applyLogger), start: time.Now()}}
+}
+
+// Middleware is a runner middleware like [CodeLineLogger.Middleware].
+func (l *GitHubLogger) Middleware(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		if in.Logger == goyek.Logger(l) {
+			in.Logger = &GitHubLogger{CodeLineLogger: CodeLineLogger{cfg: l.config(), start: time.Now()}}
+		}
+		return next(in)
+	}
+}
+
+// Error is used internally in order to report the error annotation.
+func (l *GitHubLogger) Error(w io.Writer, args ...interface{}) {
+	l.annotate(w, "error", fmt.Sprint(args...))
+}
+
+// Errorf is used internally in order to report the error annotation.
+func (l *GitHubLogger) Errorf(w io.Writer, format string, args ...interface{}) {
+	l.annotate(w, "error", fmt.Sprintf(format, args...))
+}
+
+// Fatal is used internally in order to report the error annotation.
+func (l *GitHubLogger) Fatal(w io.Writer, args ...interface{}) {
+	l.annotate(w, "error", fmt.Sprint(args...))
+}
+
+// Fatalf is used internally in order to report the error annotation.
+func (l *GitHubLogger) Fatalf(w io.Writer, format string, args ...interface{}) {
+	l.annotate(w, "error", fmt.Sprintf(format, args...))
+}
+
+// Skip is used internally in order to report the warning annotation.
+func (l *GitHubLogger) Skip(w io.Writer, args ...interface{}) {
+	l.annotate(w, "warning", fmt.Sprint(args...))
+}
+
+// Skipf is used internally in order to report the warning annotation.
+func (l *GitHubLogger) Skipf(w io.Writer, format string, args ...interface{}) {
+	l.annotate(w, "warning", fmt.Sprintf(format, args...))
+}
+
+// annotate writes the workflow command for the call site.
+func (l *GitHubLogger) annotate(w io.Writer, command, msg string) {
+	const skip = 2 // skip: GitHubLogger.annotate + GitHubLogger.Error
+	frame := l.frameSkip(skip)
+	sb := &strings.Builder{}
+	sb.WriteString("::")
+	sb.WriteString(command)
+	if frame.File != "" {
+		line := frame.Line
+		if line == 0 {
+			line = 1
+		}
+		fmt.Fprintf(sb, " file=%s,line=%d", escapeProperty(workspacePath(frame.File)), line)
+	}
+	sb.WriteString("::")
+	sb.WriteString(escapeData(strings.TrimSuffix(msg, "\n")))
+	sb.WriteByte('\n')
+	writeString(w, sb.String())
+}
+
+// workspacePath returns the file path relative to the GITHUB_WORKSPACE
+// directory if the file is inside it.
+func workspacePath(file string) string {
+	ws := os.Getenv("GITHUB_WORKSPACE")
+	if ws == "" {
+		return file
+	}
+	rel, err := filepath.Rel(ws, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes the property value of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// ReportGitHub is a runner middleware, which groups the output of each task
+// in the GitHub Actions log using the group and endgroup workflow commands.
+func ReportGitHub(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		out := outputOrDiscard(in.Output)
+		in.Output = out
+
+		writeString(out, "::group::"+escapeData(in.TaskName)+"\n")
+		res := next(in)
+		writeString(out, "::endgroup::\n")
+		return res
+	}
+}
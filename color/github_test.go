@@ -0,0 +1,94 @@
+package color_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestGitHubLogger(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_WORKSPACE", wd)
+
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(&goyekcolor.GitHubLogger{})
+	flow.Define(goyek.Task{
+		Name: "error",
+		Action: func(a *goyek.A) {
+			a.Log("message")
+			a.Errorf("100%% wrong:\nsecond line")
+			helperFn(a)
+		},
+	})
+	flow.Define(goyek.Task{
+		Name: "skip",
+		Action: func(a *goyek.A) {
+			a.Skip("not needed")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"error"})
+	_ = flow.Execute(context.Background(), []string{"skip"})
+
+	for _, want := range []string{
+		"      github_test.go:29: message\n",
+		"::error file=github_test.go,line=30::100%25 wrong:%0Asecond line\n",
+		"      github_test.go:31: message from helper\n",
+		"::warning file=github_test.go,line=37::not needed\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q does not contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestGitHubLoggerOutsideWorkspace(t *testing.T) {
+	t.Setenv("GITHUB_WORKSPACE", t.TempDir())
+
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(goyekcolor.NewGitHubLogger())
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Fatal("failure")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	got := out.String()
+	if !strings.HasPrefix(got, "::error file=") || strings.HasPrefix(got, "::error file=github_test.go") ||
+		!strings.HasSuffix(got, "/github_test.go,line=66::failure\n") {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+func TestReportGitHub(t *testing.T) {
+	out := &strings.Builder{}
+	runner := goyekcolor.ReportGitHub(func(in goyek.Input) goyek.Result {
+		_, _ = io.WriteString(in.Output, "task output\n")
+		return goyek.Result{Status: goyek.StatusPassed}
+	})
+
+	res := runner(goyek.Input{Output: out, TaskName: "build"})
+
+	if res.Status != goyek.StatusPassed {
+		t.Errorf("got status %v, want %v", res.Status, goyek.StatusPassed)
+	}
+	if got, want := out.String(), "::group::build\ntask output\n::endgroup::\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// ReportGitLab is a runner middleware, which wraps the output of each task
+// in a collapsible section of the GitLab CI job log.
+// GitLab shows the duration of the section using its timestamps.
+func ReportGitLab(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		out := outputOrDiscard(in.Output)
+		in.Output = out
+		name := gitLabSection(in.TaskName)
+
+		writeString(out, fmt.Sprintf("\x1b[0Ksection_start:%d:%s\r\x1b[0K%s\n", time.Now().Unix(), name, in.TaskName))
+		res := next(in)
+		writeString(out, fmt.Sprintf("\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), name))
+		return res
+	}
+}
+
+// gitLabSection returns the section name containing only the characters
+// allowed by GitLab.
+func gitLabSection(taskName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, taskName)
+	return "goyek_" + name
+}
@@ -0,0 +1,29 @@
+package color_test
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestReportGitLab(t *testing.T) {
+	out := &strings.Builder{}
+	runner := goyekcolor.ReportGitLab(func(in goyek.Input) goyek.Result {
+		_, _ = io.WriteString(in.Output, "task output\n")
+		return goyek.Result{Status: goyek.StatusPassed}
+	})
+
+	runner(goyek.Input{Output: out, TaskName: "go test"})
+
+	want := regexp.MustCompile(`^\x1b\[0Ksection_start:\d+:goyek_go_test\r\x1b\[0Kgo test\n` +
+		`task output\n` +
+		`\x1b\[0Ksection_end:\d+:goyek_go_test\r\x1b\[0K\n$`)
+	if got := out.String(); !want.MatchString(got) {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
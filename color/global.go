@@ -1,23 +1,29 @@
 // Package color contains goyek features which additionally
 // have colors.
 //
+// The output is colorized only when it is written to a terminal.
+// The output written to buffers, like the ones used by middlewares,
+// is colorized only if SetFlowOutput is used with a terminal.
 // Set NO_COLOR environment variable to a non-empty string
 // or use the NoColor function to prevent colorizing the output.
+// Set FORCE_COLOR or CLICOLOR_FORCE environment variable to a non-empty
+// string to colorize the output even if it is not a terminal.
+//
+// Set GOYEK_COLOR_THEME environment variable to "high-contrast"
+// or "colorblind" to change the default theme.
+// Themes may use RGB colors which are downgraded to 256 or 16 colors
+// depending on the COLORTERM and TERM environment variables.
+//
+// Set GOYEK_LOG_LEVEL environment variable to "debug", "info", or "warn"
+// to change the minimum level of the records logged using Debug, Info, and Warn.
 package color
 
-import (
-	"os"
+import "sync/atomic"
 
-	"github.com/fatih/color"
-)
-
-func init() {
-	color.NoColor = os.Getenv("NO_COLOR") != ""
-}
+var noColor atomic.Bool
 
 // NoColor prevents colorizing the output.
-// It changes process-wide state and must be called during program
-// initialization, before any goroutine can format colored output.
+// It has precedence over the environment variables.
 func NoColor() {
-	color.NoColor = true
+	noColor.Store(true)
 }
@@ -0,0 +1,76 @@
+package color
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// ReportHeartbeat returns a runner middleware which prints
+// a line like "... still running: test (5m0s)" every interval
+// while the task is running, so that the output of long-running tasks
+// is not silent and CI systems do not stop the job for inactivity.
+//
+// The lines use the Info style of the theme. They use the Warn style
+// and the Failed style after the thresholds configured using
+// [WithHeartbeatThresholds] are crossed. By default, the thresholds are
+// 5 and 10 times the interval.
+//
+// It has to be used after middlewares which buffer the output,
+// like middleware.SilentNonFailed, so that the lines are printed immediately.
+// It does nothing if the interval is not positive.
+func ReportHeartbeat(interval time.Duration, opts ...HeartbeatOption) goyek.Middleware {
+	const warnTicks, criticalTicks = 5, 10
+	defaults := WithHeartbeatThresholds(warnTicks*interval, criticalTicks*interval)
+	cfg := newConfig(append([]HeartbeatOption{defaults}, opts...), HeartbeatOption.applyHeartbeat)
+	return func(next goyek.Runner) goyek.Runner {
+		if interval <= 0 {
+			return next
+		}
+		return func(in goyek.Input) goyek.Result {
+			out := outputOrDiscard(in.Output)
+			in.Output = out
+
+			start := time.Now()
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						elapsed := time.Since(start)
+						style := cfg.heartbeatStyle(elapsed)
+						writeString(out, style.sprintf(Enabled(out), "... still running: %s (%s)\n",
+							in.TaskName, elapsed.Round(time.Second)))
+					}
+				}
+			}()
+
+			res := next(in)
+
+			close(done)
+			wg.Wait()
+			return res
+		}
+	}
+}
+
+// heartbeatStyle returns the style of the heartbeat line
+// escalated according to the elapsed time.
+func (cfg *config) heartbeatStyle(elapsed time.Duration) Style {
+	switch {
+	case cfg.HeartbeatCritical > 0 && elapsed >= cfg.HeartbeatCritical:
+		return cfg.Theme.Failed
+	case cfg.HeartbeatWarn > 0 && elapsed >= cfg.HeartbeatWarn:
+		return cfg.Theme.Warn
+	default:
+		return cfg.Theme.Info
+	}
+}
@@ -0,0 +1,73 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestReportHeartbeat(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	out := &strings.Builder{}
+	runner := goyekcolor.ReportHeartbeat(10 * time.Millisecond)(func(goyek.Input) goyek.Result {
+		time.Sleep(55 * time.Millisecond)
+		return goyek.Result{Status: goyek.StatusPassed}
+	})
+
+	res := runner(goyek.Input{Output: goyek.SyncWriter(out), TaskName: "task"})
+
+	if res.Status != goyek.StatusPassed {
+		t.Errorf("got status %v, want %v", res.Status, goyek.StatusPassed)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("got %d heartbeats, want at least 2:\n%s", len(lines), out.String())
+	}
+	for _, line := range lines {
+		if line != "... still running: task (0s)" {
+			t.Errorf("unexpected heartbeat: %q", line)
+		}
+	}
+}
+
+func TestReportHeartbeatEscalation(t *testing.T) {
+	forceColor(t)
+	out := &strings.Builder{}
+	mw := goyekcolor.ReportHeartbeat(20*time.Millisecond,
+		goyekcolor.WithHeartbeatThresholds(50*time.Millisecond, 90*time.Millisecond))
+	runner := mw(func(goyek.Input) goyek.Result {
+		time.Sleep(150 * time.Millisecond)
+		return goyek.Result{Status: goyek.StatusPassed}
+	})
+
+	runner(goyek.Input{Output: goyek.SyncWriter(out), TaskName: "task"})
+
+	got := out.String()
+	if !strings.HasPrefix(got, "... still running: task (0s)\n") {
+		t.Errorf("first heartbeat should not be colored:\n%q", got)
+	}
+	if !strings.Contains(got, "\x1b[93m... still running: task (0s)\n") {
+		t.Errorf("should contain a warning heartbeat:\n%q", got)
+	}
+	if !strings.HasSuffix(got, ansiRed+"... still running: task (0s)\n"+ansiReset) {
+		t.Errorf("last heartbeat should be red:\n%q", got)
+	}
+}
+
+func TestReportHeartbeatDisabled(t *testing.T) {
+	out := &strings.Builder{}
+	runner := goyekcolor.ReportHeartbeat(0)(func(goyek.Input) goyek.Result {
+		time.Sleep(10 * time.Millisecond)
+		return goyek.Result{Status: goyek.StatusPassed}
+	})
+
+	runner(goyek.Input{Output: out, TaskName: "task"})
+
+	if got := out.String(); got != "" {
+		t.Errorf("got %q, want no output", got)
+	}
+}
@@ -0,0 +1,443 @@
+package color
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HTMLWriter converts the colored output into a standalone HTML page.
+//
+// The ANSI colors and text attributes are converted into styled text
+// and OSC 8 hyperlinks into links. The output of each task reported
+// by [ReportStatus] is put in a collapsible section with an anchor
+// named "task-" followed by the task name. The sections of failed tasks
+// are expanded. The page starts with the list of links to the sections.
+// The tasks are recognized only if the default status format is used.
+// The output of parallel tasks is put in the proper sections only
+// if it is not interleaved, like when middleware.BufferParallel is used,
+// or if its lines are prefixed with the task name by [PrefixParallel].
+//
+// Only the links using the file, http, or https scheme, or the scheme
+// of the template given using [WithLinks] are kept. Other links,
+// like javascript URLs, are written as plain text.
+//
+// The page is written to the underlying writer when Close is called.
+// HTMLWriter is safe for concurrent use.
+//
+// The output written only to the HTMLWriter is not a terminal,
+// so set FORCE_COLOR to keep the colors. For example:
+//
+//	hw := color.NewHTMLWriter(f)
+//	defer hw.Close()
+//	goyek.SetOutput(io.MultiWriter(os.Stdout, hw))
+type HTMLWriter struct {
+	w          io.Writer
+	linkScheme string // scheme allowed in addition to file, http, and https
+
+	mu       sync.Mutex
+	partial  []byte
+	state    sgrState
+	span     bool   // whether a span with the style is open
+	url      string // target of the open hyperlink
+	sections []*htmlSection
+	current  *htmlSection
+	open     map[string]*htmlSection // task sections not ended yet
+	closed   bool
+}
+
+// htmlSection is a part of the output. Sections with a task name
+// contain the output of the task.
+type htmlSection struct {
+	task   string
+	status string
+	body   strings.Builder
+}
+
+var (
+	htmlTaskStart = regexp.MustCompile(`^===== TASK  (.+)$`)
+	htmlTaskEnd   = regexp.MustCompile(`^----- (PASS|FAIL|SKIP|NOOP): (.+) \(\d+\.\d\ds\)$`)
+	htmlFlowEnd   = regexp.MustCompile(`\t\d+\.\d{3}s$`)
+	htmlTaskLine  = regexp.MustCompile(`^\[([^\]]+)\] (.*)$`)
+	urlScheme     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*$`)
+)
+
+// NewHTMLWriter returns an HTMLWriter writing the page to w
+// configured using the options.
+func NewHTMLWriter(w io.Writer, opts ...HTMLOption) *HTMLWriter {
+	cfg := newConfig(opts, HTMLOption.applyHTML)
+	return &HTMLWriter{w: w, linkScheme: linkScheme(cfg.Links), open: map[string]*htmlSection{}}
+}
+
+// Write converts the output. Incomplete lines are buffered
+// until they are completed or the writer is closed.
+func (hw *HTMLWriter) Write(p []byte) (int, error) {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+	if hw.closed {
+		return 0, io.ErrClosedPipe
+	}
+	hw.partial = append(hw.partial, p...)
+	for {
+		i := bytes.IndexByte(hw.partial, '\n')
+		if i < 0 {
+			break
+		}
+		hw.line(string(hw.partial[:i]))
+		hw.partial = hw.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Close converts the buffered incomplete line
+// and writes the HTML page to the underlying writer.
+func (hw *HTMLWriter) Close() error {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+	if hw.closed {
+		return nil
+	}
+	hw.closed = true
+	if len(hw.partial) > 0 {
+		if stripANSI(string(hw.partial)) == "" {
+			// Only update the state, like for the final reset.
+			hw.convert(&strings.Builder{}, string(hw.partial))
+		} else {
+			hw.line(string(hw.partial))
+		}
+		hw.partial = nil
+	}
+	_, err := io.WriteString(hw.w, hw.page())
+	return err
+}
+
+// line converts the line and adds it to the proper section.
+func (hw *HTMLWriter) line(s string) {
+	s = strings.ReplaceAll(s, "\r", "")
+	sec := hw.section(stripANSI(s))
+	hw.convert(&sec.body, s)
+	sec.body.WriteByte('\n')
+}
+
+// section returns the section of the line. The lines prefixed
+// with the name of a running task are put in its section.
+// Other lines are put in the section of the last started
+// or ended task.
+func (hw *HTMLWriter) section(plain string) *htmlSection {
+	task, text := "", plain
+	if m := htmlTaskLine.FindStringSubmatch(plain); m != nil {
+		task, text = m[1], m[2]
+	}
+	if m := htmlTaskStart.FindStringSubmatch(text); m != nil && ofTask(task, m[1]) {
+		hw.current = &htmlSection{task: m[1]}
+		hw.sections = append(hw.sections, hw.current)
+		hw.open[m[1]] = hw.current
+		return hw.current
+	}
+	if m := htmlTaskEnd.FindStringSubmatch(text); m != nil && ofTask(task, m[2]) {
+		if sec, ok := hw.open[m[2]]; ok {
+			sec.status = m[1]
+			delete(hw.open, m[2])
+			hw.current = sec
+			return sec
+		}
+	}
+	if sec, ok := hw.open[task]; ok && task != "" {
+		return sec
+	}
+	if hw.current != nil && hw.current.status != "" && len(hw.open) == 0 && htmlFlowEnd.MatchString(plain) {
+		// The flow end is recognized only after the tasks end,
+		// so that the task output like "ok  \tpkg\t0.512s" is kept in the task.
+		hw.current = nil
+	}
+	if hw.current == nil {
+		hw.current = &htmlSection{}
+		hw.sections = append(hw.sections, hw.current)
+	}
+	return hw.current
+}
+
+// ofTask reports whether the line prefixed with the task name,
+// or not prefixed if the name is empty, belongs to the named task.
+func ofTask(prefix, name string) bool {
+	return prefix == "" || prefix == name
+}
+
+// convert writes the line as HTML. The spans are closed at the end
+// of each line and reopened when needed so that every line is well-formed.
+func (hw *HTMLWriter) convert(sb *strings.Builder, s string) {
+	if hw.url != "" {
+		fmt.Fprintf(sb, `<a href="%s">`, html.EscapeString(hw.url))
+	}
+	pos := 0
+	for _, loc := range ansiEscape.FindAllStringIndex(s, -1) {
+		hw.text(sb, s[pos:loc[0]])
+		pos = loc[1]
+		seq := s[loc[0]:loc[1]]
+		switch {
+		case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"):
+			hw.closeSpan(sb)
+			hw.state.apply(seq[2 : len(seq)-1])
+		case strings.HasPrefix(seq, "\x1b]8;"):
+			hw.hyperlink(sb, seq)
+		}
+	}
+	hw.text(sb, s[pos:])
+	hw.closeSpan(sb)
+	if hw.url != "" {
+		sb.WriteString(`</a>`)
+	}
+}
+
+// text writes the escaped text in a span with the current style.
+func (hw *HTMLWriter) text(sb *strings.Builder, s string) {
+	if s == "" {
+		return
+	}
+	if css := hw.state.css(); css != "" && !hw.span {
+		fmt.Fprintf(sb, `<span style="%s">`, css)
+		hw.span = true
+	}
+	sb.WriteString(html.EscapeString(s))
+}
+
+func (hw *HTMLWriter) closeSpan(sb *strings.Builder) {
+	if hw.span {
+		sb.WriteString("</span>")
+		hw.span = false
+	}
+}
+
+// hyperlink converts the OSC 8 sequence into the start or end of a link.
+func (hw *HTMLWriter) hyperlink(sb *strings.Builder, seq string) {
+	seq = strings.TrimSuffix(strings.TrimSuffix(seq, "\x07"), "\x1b\\")
+	_, url, _ := strings.Cut(strings.TrimPrefix(seq, "\x1b]8;"), ";")
+	hw.closeSpan(sb)
+	if hw.url != "" {
+		sb.WriteString(`</a>`)
+	}
+	hw.url = ""
+	if hw.allowedLink(url) {
+		hw.url = url
+		fmt.Fprintf(sb, `<a href="%s">`, html.EscapeString(url))
+	}
+}
+
+// allowedLink reports whether the link can be put in the page.
+func (hw *HTMLWriter) allowedLink(link string) bool {
+	switch scheme := linkScheme(link); scheme {
+	case "":
+		return false
+	case "file", "http", "https":
+		return true
+	default:
+		return scheme == hw.linkScheme
+	}
+}
+
+// linkScheme returns the lower case URL scheme of the link or the template,
+// or an empty string if there is none.
+func linkScheme(link string) string {
+	scheme, _, ok := strings.Cut(link, ":")
+	if !ok || !urlScheme.MatchString(scheme) {
+		return ""
+	}
+	return strings.ToLower(scheme)
+}
+
+// page returns the HTML page.
+func (hw *HTMLWriter) page() string {
+	sb := &strings.Builder{}
+	sb.WriteString(htmlHeader)
+	sb.WriteString("<nav><ul>\n")
+	for _, sec := range hw.sections {
+		if sec.task != "" {
+			fmt.Fprintf(sb, "<li><a href=\"#%s\" class=\"%s\">%s</a> %s</li>\n",
+				sec.anchor(), sec.class(), html.EscapeString(sec.task), sec.status)
+		}
+	}
+	sb.WriteString("</ul></nav>\n")
+	for _, sec := range hw.sections {
+		if sec.task == "" {
+			fmt.Fprintf(sb, "<pre>%s</pre>\n", sec.body.String())
+			continue
+		}
+		open := ""
+		if sec.status == "FAIL" {
+			open = " open"
+		}
+		fmt.Fprintf(sb, "<details id=\"%s\"%s><summary class=\"%s\">%s %s</summary><pre>%s</pre></details>\n",
+			sec.anchor(), open, sec.class(), html.EscapeString(sec.task), sec.status, sec.body.String())
+	}
+	sb.WriteString(htmlFooter)
+	return sb.String()
+}
+
+// anchor returns the identifier of the task section.
+func (sec *htmlSection) anchor() string {
+	return html.EscapeString("task-" + strings.Join(strings.Fields(sec.task), "-"))
+}
+
+// class returns the CSS class of the task status.
+func (sec *htmlSection) class() string {
+	if sec.status == "" {
+		return "running"
+	}
+	return strings.ToLower(sec.status)
+}
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>goyek</title>
+<style>
+body { background: #1e1e1e; color: #e5e5e5; font-family: sans-serif; }
+pre { margin: 0; font-family: monospace; white-space: pre-wrap; }
+a { color: inherit; }
+summary { cursor: pointer; font-family: monospace; }
+details { margin: 0.25em 0; }
+.pass { color: #00cd00; }
+.fail { color: #ff5f5f; }
+.skip, .noop, .running { color: #cdcd00; }
+</style>
+</head>
+<body>
+`
+
+const htmlFooter = `</body>
+</html>
+`
+
+// sgrState is the text style set by SGR sequences.
+type sgrState struct {
+	bold, faint, italic, underline bool
+	fg, bg                         string // CSS colors
+}
+
+func (st *sgrState) css() string {
+	var props []string
+	if st.bold {
+		props = append(props, "font-weight:bold")
+	}
+	if st.faint {
+		props = append(props, "opacity:0.6")
+	}
+	if st.italic {
+		props = append(props, "font-style:italic")
+	}
+	if st.underline {
+		props = append(props, "text-decoration:underline")
+	}
+	if st.fg != "" {
+		props = append(props, "color:"+st.fg)
+	}
+	if st.bg != "" {
+		props = append(props, "background-color:"+st.bg)
+	}
+	return strings.Join(props, ";")
+}
+
+// apply updates the style using the SGR parameters.
+func (st *sgrState) apply(params string) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			code = sgrReset // an empty parameter means reset
+		}
+		switch code {
+		case sgrReset:
+			*st = sgrState{}
+		case sgrBold:
+			st.bold = true
+		case sgrFaint:
+			st.faint = true
+		case sgrItalic:
+			st.italic = true
+		case sgrUnderline:
+			st.underline = true
+		case sgrNormal:
+			st.bold, st.faint = false, false
+		case sgrNoItalic:
+			st.italic = false
+		case sgrNoUnderline:
+			st.underline = false
+		case sgrFgDefault:
+			st.fg = ""
+		case sgrBgDefault:
+			st.bg = ""
+		case sgrFgExt, sgrFgExt + sgrBgShift:
+			c, n := extendedColor(codes[i+1:])
+			i += n
+			if code == sgrFgExt {
+				st.fg = c
+			} else {
+				st.bg = c
+			}
+		default:
+			st.applyBasic(code)
+		}
+	}
+}
+
+// applyBasic updates the colors using the SGR parameter
+// of the basic palette.
+func (st *sgrState) applyBasic(code int) {
+	switch {
+	case code >= sgrFg && code < sgrFg+basicColors:
+		st.fg = cssColor(ansi16Palette[code-sgrFg])
+	case code >= sgrFgHi && code < sgrFgHi+basicColors:
+		st.fg = cssColor(ansi16Palette[code-sgrFgHi+basicColors])
+	case code >= sgrFg+sgrBgShift && code < sgrFg+sgrBgShift+basicColors:
+		st.bg = cssColor(ansi16Palette[code-sgrFg-sgrBgShift])
+	case code >= sgrFgHi+sgrBgShift && code < sgrFgHi+sgrBgShift+basicColors:
+		st.bg = cssColor(ansi16Palette[code-sgrFgHi-sgrBgShift+basicColors])
+	}
+}
+
+// extendedColor parses the 256 color or 24-bit color parameters
+// following 38 or 48 and returns the CSS color
+// and the number of parameters used.
+func extendedColor(params []string) (string, int) {
+	nums := make([]int, 0, len(params))
+	for _, p := range params {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 255 {
+			break
+		}
+		nums = append(nums, n)
+	}
+	switch {
+	case len(nums) >= 2 && nums[0] == sgrExt256:
+		return cssColor(xterm256(nums[1])), 2 //nolint:mnd // mode and index
+	case len(nums) >= 4 && nums[0] == sgrExtRGB:
+		return cssColor(RGB{uint8(nums[1]), uint8(nums[2]), uint8(nums[3])}), 4 //nolint:gosec,mnd // checked range; mode and components
+	default:
+		return "", len(nums)
+	}
+}
+
+// xterm256 returns the color of the xterm 256 color palette.
+func xterm256(i int) RGB {
+	const cubeStart, grayStart = 16, 232
+	switch {
+	case i < cubeStart:
+		return ansi16Palette[i]
+	case i < grayStart:
+		i -= cubeStart
+		return RGB{cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]} //nolint:mnd // 6x6x6 color cube
+	default:
+		v := uint8(8 + 10*(i-grayStart)) //nolint:gosec,mnd // gray levels are 8 + 10*i
+		return RGB{v, v, v}
+	}
+}
+
+func cssColor(c RGB) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,201 @@
+package color_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestHTMLWriter(t *testing.T) {
+	forceColor(t)
+	out := &strings.Builder{}
+	hw := goyekcolor.NewHTMLWriter(out)
+	flow := &goyek.Flow{}
+	flow.SetOutput(hw)
+	flow.SetLogger(&goyekcolor.CodeLineLogger{})
+	flow.Use(goyekcolor.ReportStatus)
+	flow.UseExecutor(goyekcolor.ReportFlow)
+	pass := flow.Define(goyek.Task{
+		Name: "pass",
+		Action: func(a *goyek.A) {
+			a.Log("a < b")
+		},
+	})
+	flow.Define(goyek.Task{
+		Name: "fail",
+		Deps: goyek.Deps{pass},
+		Action: func(a *goyek.A) {
+			a.Error("failure")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"fail"})
+	if err := hw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<li><a href="#task-pass" class="pass">pass</a> PASS</li>`,
+		`<li><a href="#task-fail" class="fail">fail</a> FAIL</li>`,
+		`<details id="task-pass"><summary class="pass">pass PASS</summary><pre>` +
+			`<span style="color:#0000ee">===== TASK  pass</span>` + "\n",
+		"html_test.go:26: a &lt; b\n",
+		`<span style="color:#00cd00">----- PASS: pass (`,
+		`<details id="task-fail" open><summary class="fail">fail FAIL</summary>`,
+		`<span style="color:#cd0000">      html_test.go:33: failure</span>`,
+		`<pre><span style="font-weight:bold;color:#cd0000">`,
+		"</body>\n</html>\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got:\n%s\nshould contain:\n%s", got, want)
+		}
+	}
+}
+
+func TestHTMLWriterSequences(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "256 colors",
+			in:   "\x1b[38;5;208mtext\x1b[0m",
+			want: `<pre><span style="color:#ff8700">text</span>` + "\n</pre>",
+		},
+		{
+			name: "true color background",
+			in:   "\x1b[1;48;2;1;2;3mtext\x1b[22mmore\x1b[0m",
+			want: `<pre><span style="font-weight:bold;background-color:#010203">text</span>` +
+				`<span style="background-color:#010203">more</span>` + "\n</pre>",
+		},
+		{
+			name: "style across lines",
+			in:   "\x1b[31mone\ntwo\x1b[0m",
+			want: `<pre><span style="color:#cd0000">one</span>` + "\n" +
+				`<span style="color:#cd0000">two</span>` + "\n</pre>",
+		},
+		{
+			name: "hyperlink",
+			in:   "\x1b]8;;file:///a.go\x1b\\a.go:1\x1b]8;;\x1b\\: msg",
+			want: `<pre><a href="file:///a.go">a.go:1</a>: msg` + "\n</pre>",
+		},
+		{
+			name: "javascript link",
+			in:   "\x1b]8;;javascript:alert(1)\x1b\\text\x1b]8;;\x1b\\",
+			want: "<pre>text\n</pre>",
+		},
+		{
+			name: "link without scheme",
+			in:   "\x1b]8;;a.go\x1b\\text\x1b]8;;\x1b\\",
+			want: "<pre>text\n</pre>",
+		},
+		{
+			name: "editor link",
+			in:   "\x1b]8;;vscode://file/a.go:1\x1b\\text\x1b]8;;\x1b\\",
+			want: "<pre>text\n</pre>",
+		},
+		{
+			name: "other sequences",
+			in:   "\x1b[0Ksection\r\x1b[0K",
+			want: "<pre>section\n</pre>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &strings.Builder{}
+			hw := goyekcolor.NewHTMLWriter(out)
+
+			_, _ = hw.Write([]byte(tt.in))
+			if err := hw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := out.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("got:\n%s\nshould contain:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLWriterLinks(t *testing.T) {
+	out := &strings.Builder{}
+	hw := goyekcolor.NewHTMLWriter(out, goyekcolor.WithLinks("vscode://file/{path}:{line}"))
+
+	_, _ = hw.Write([]byte("\x1b]8;;vscode://file/a.go:1\x1b\\a.go:1\x1b]8;;\x1b\\\n"))
+	_, _ = hw.Write([]byte("\x1b]8;;javascript:alert(1)\x1b\\text\x1b]8;;\x1b\\"))
+	if err := hw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<pre><a href="vscode://file/a.go:1">a.go:1</a>` + "\ntext\n</pre>"
+	if got := out.String(); !strings.Contains(got, want) {
+		t.Errorf("got:\n%s\nshould contain:\n%s", got, want)
+	}
+}
+
+func TestHTMLWriterTaskOutputLikeFlowEnd(t *testing.T) {
+	out := &strings.Builder{}
+	hw := goyekcolor.NewHTMLWriter(out)
+	flow := &goyek.Flow{}
+	flow.SetOutput(hw)
+	flow.Use(goyekcolor.ReportStatus)
+	flow.UseExecutor(goyekcolor.ReportFlow)
+	flow.Define(goyek.Task{
+		Name: "test",
+		Action: func(a *goyek.A) {
+			_, _ = io.WriteString(a.Output(), "ok\tpkg\t0.123s\nafter\n")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"test"})
+	if err := hw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	want := "ok\tpkg\t0.123s\nafter\n----- PASS: test ("
+	if !strings.Contains(got, want) {
+		t.Errorf("got:\n%s\nshould contain:\n%s", got, want)
+	}
+	if !strings.Contains(got, "</details>\n<pre>ok\t") {
+		t.Errorf("got:\n%s\nshould contain the flow end after the task section", got)
+	}
+}
+
+func TestHTMLWriterPrefixedParallel(t *testing.T) {
+	out := &strings.Builder{}
+	hw := goyekcolor.NewHTMLWriter(out)
+
+	_, _ = io.WriteString(hw, "===== TASK  a\n"+
+		"[b] ===== TASK  b\n"+
+		"[a] output a\n"+
+		"[b] output b\n"+
+		"----- PASS: a (0.00s)\n"+
+		"[b] more b\n"+
+		"[b] ----- FAIL: b (0.01s)\n"+
+		"FAIL\t0.012s\n")
+	if err := hw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		`<li><a href="#task-a" class="pass">a</a> PASS</li>`,
+		`<li><a href="#task-b" class="fail">b</a> FAIL</li>`,
+		`<summary class="pass">a PASS</summary><pre>===== TASK  a` + "\n[a] output a\n----- PASS: a (0.00s)\n</pre>",
+		`<summary class="fail">b FAIL</summary><pre>[b] ===== TASK  b` + "\n[b] output b\n[b] more b\n[b] ----- FAIL: b (0.01s)\n</pre>",
+		"</details>\n<pre>FAIL\t0.012s\n</pre>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got:\n%s\nshould contain:\n%s", got, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package color
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// JSONLogger writes the log records as JSON lines, which are easy to parse
+// by log aggregators. Each record contains the time, the task name,
+// the level (debug, info, warn, error, fatal, or skip), the file, line,
+// and function of the call site, and the message.
+//
+// The task name is included only when [JSONLogger.Middleware] is used.
+// The call site is detected like in [CodeLineLogger].
+//
+// The zero value is ready to use.
+type JSONLogger struct {
+	lines CodeLineLogger // used to detect the call site
+	task  string
+}
+
+// jsonRecord is a record written by JSONLogger.
+type jsonRecord struct {
+	Time  time.Time `json:"time"`
+	Task  string    `json:"task,omitempty"`
+	Level string    `json:"level"`
+	File  string    `json:"file"`
+	Line  int       `json:"line"`
+	Func  string    `json:"func"`
+	Msg   string    `json:"msg"`
+}
+
+// Middleware is a runner middleware, which makes the logger include
+// the task name in the records.
+//
+// It uses a copy of the logger for each task. Therefore, it has to be
+// used after middlewares which wrap the logger, like [Summary.Middleware].
+func (l *JSONLogger) Middleware(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		if in.Logger == goyek.Logger(l) {
+			in.Logger = &JSONLogger{task: in.TaskName}
+		}
+		return next(in)
+	}
+}
+
+// Log is used internally in order to write the record.
+func (l *JSONLogger) Log(w io.Writer, args ...interface{}) {
+	if len(args) == 1 {
+		if r, ok := args[0].(levelRecord); ok {
+			l.write(w, r.level.String(), r.msg)
+			return
+		}
+	}
+	l.write(w, "info", fmt.Sprint(args...))
+}
+
+// Logf is used internally in order to write the record.
+func (l *JSONLogger) Logf(w io.Writer, format string, args ...interface{}) {
+	l.write(w, "info", fmt.Sprintf(format, args...))
+}
+
+// Error is used internally in order to write the record.
+func (l *JSONLogger) Error(w io.Writer, args ...interface{}) {
+	l.write(w, "error", fmt.Sprint(args...))
+}
+
+// Errorf is used internally in order to write the record.
+func (l *JSONLogger) Errorf(w io.Writer, format string, args ...interface{}) {
+	l.write(w, "error", fmt.Sprintf(format, args...))
+}
+
+// Fatal is used internally in order to write the record.
+func (l *JSONLogger) Fatal(w io.Writer, args ...interface{}) {
+	l.write(w, "fatal", fmt.Sprint(args...))
+}
+
+// Fatalf is used internally in order to write the record.
+func (l *JSONLogger) Fatalf(w io.Writer, format string, args ...interface{}) {
+	l.write(w, "fatal", fmt.Sprintf(format, args...))
+}
+
+// Skip is used internally in order to write the record.
+func (l *JSONLogger) Skip(w io.Writer, args ...interface{}) {
+	l.write(w, "skip", fmt.Sprint(args...))
+}
+
+// Skipf is used internally in order to write the record.
+func (l *JSONLogger) Skipf(w io.Writer, format string, args ...interface{}) {
+	l.write(w, "skip", fmt.Sprintf(format, args...))
+}
+
+// Helper marks the calling function as a helper function.
+// When writing file and line information, that function will be skipped.
+// Helper may be called simultaneously from multiple goroutines.
+func (l *JSONLogger) Helper() {
+	l.lines.Helper()
+}
+
+func (l *JSONLogger) write(w io.Writer, level, msg string) {
+	const skip = 2 // skip: JSONLogger.write + JSONLogger.Log
+	frame := l.lines.frameSkip(skip)
+	rec := jsonRecord{
+		Time:  time.Now(),
+		Task:  l.task,
+		Level: level,
+		File:  frame.File,
+		Line:  frame.Line,
+		Func:  frame.Function,
+		Msg:   msg,
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	writeString(w, string(b)+"\n")
+}
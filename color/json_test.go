@@ -0,0 +1,86 @@
+package color_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+type jsonRecord struct {
+	Time  time.Time `json:"time"`
+	Task  string    `json:"task"`
+	Level string    `json:"level"`
+	File  string    `json:"file"`
+	Line  int       `json:"line"`
+	Func  string    `json:"func"`
+	Msg   string    `json:"msg"`
+}
+
+func TestJSONLogger(t *testing.T) {
+	logger := &goyekcolor.JSONLogger{}
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(logger)
+	flow.Use(logger.Middleware)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Logf("hello %s", "world")
+			helperFn(a)
+			a.Error("multi\nline")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	want := []jsonRecord{
+		{Task: "task", Level: "info", Line: 35, Msg: "hello world"},
+		{Task: "task", Level: "info", Line: 36, Msg: "message from helper"},
+		{Task: "task", Level: "error", Line: 37, Msg: "multi\nline"},
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d records, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, line := range lines {
+		var got jsonRecord
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		if got.Time.IsZero() || !strings.HasSuffix(got.File, "/color/json_test.go") ||
+			!strings.HasPrefix(got.Func, "github.com/goyek/x/color_test.TestJSONLogger") {
+			t.Errorf("unexpected record: %s", line)
+		}
+		got.Time, got.File, got.Func = time.Time{}, "", ""
+		if got != want[i] {
+			t.Errorf("got record %+v, want %+v", got, want[i])
+		}
+	}
+}
+
+func TestJSONLoggerWithoutMiddleware(t *testing.T) {
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(&goyekcolor.JSONLogger{})
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Skip("skipped")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	got := out.String()
+	if strings.Contains(got, `"task"`) || !strings.Contains(got, `"level":"skip"`) || !strings.Contains(got, `"msg":"skipped"`) {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+package color
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// levelEnv is the environment variable setting the default log level.
+const levelEnv = "GOYEK_LOG_LEVEL"
+
+// Level is the severity of the records logged by
+// [Debug], [Info], and [Warn] functions.
+type Level int
+
+// Log levels.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// ParseLevel returns the level with the given name:
+// "debug", "info", or "warn".
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	default:
+		return 0, fmt.Errorf("unknown log level: %q", s)
+	}
+}
+
+var logLevel atomic.Int64
+
+func init() {
+	lvl, err := ParseLevel(os.Getenv(levelEnv))
+	if err != nil {
+		lvl = LevelInfo
+	}
+	logLevel.Store(int64(lvl))
+}
+
+// SetLogLevel sets the minimum level of the logged records.
+// The default is [LevelInfo] unless the GOYEK_LOG_LEVEL environment
+// variable is set to "debug", "info", or "warn".
+func SetLogLevel(l Level) {
+	logLevel.Store(int64(l))
+}
+
+// LogLevel returns the minimum level of the logged records.
+func LogLevel() Level {
+	return Level(logLevel.Load())
+}
+
+// Debug logs the record like a.Log if debug records are enabled.
+func Debug(a *goyek.A, args ...interface{}) {
+	a.Helper()
+	logAt(a, LevelDebug, fmt.Sprint(args...))
+}
+
+// Debugf logs the record like a.Logf if debug records are enabled.
+func Debugf(a *goyek.A, format string, args ...interface{}) {
+	a.Helper()
+	logAt(a, LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Info logs the record like a.Log if info records are enabled.
+func Info(a *goyek.A, args ...interface{}) {
+	a.Helper()
+	logAt(a, LevelInfo, fmt.Sprint(args...))
+}
+
+// Infof logs the record like a.Logf if info records are enabled.
+func Infof(a *goyek.A, format string, args ...interface{}) {
+	a.Helper()
+	logAt(a, LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Warn logs the record like a.Log if warning records are enabled.
+func Warn(a *goyek.A, args ...interface{}) {
+	a.Helper()
+	logAt(a, LevelWarn, fmt.Sprint(args...))
+}
+
+// Warnf logs the record like a.Logf if warning records are enabled.
+func Warnf(a *goyek.A, format string, args ...interface{}) {
+	a.Helper()
+	logAt(a, LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// logAt logs the message with the label of the level.
+// CodeLineLogger colors the record using the style of the level.
+func logAt(a *goyek.A, l Level, msg string) {
+	a.Helper()
+	if l < LogLevel() {
+		return
+	}
+	a.Log(levelRecord{level: l, msg: msg})
+}
+
+// levelRecord is a message logged by Debug, Info, or Warn.
+type levelRecord struct {
+	level Level
+	msg   string
+}
+
+// String returns the message with the label of the level,
+// so that the levels can be distinguished without colors.
+func (r levelRecord) String() string {
+	return strings.ToUpper(r.level.String()) + ": " + r.msg
+}
+
+func (r levelRecord) colorize(theme Theme, colored bool) string {
+	style := theme.Info
+	switch r.level {
+	case LevelDebug:
+		style = theme.Debug
+	case LevelWarn:
+		style = theme.Warn
+	}
+	return style.sprint(colored, r.String())
+}
@@ -0,0 +1,143 @@
+package color_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fatihcolor "github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestParseLevel(t *testing.T) {
+	for _, lvl := range []goyekcolor.Level{goyekcolor.LevelDebug, goyekcolor.LevelInfo, goyekcolor.LevelWarn} {
+		got, err := goyekcolor.ParseLevel(strings.ToUpper(lvl.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != lvl {
+			t.Errorf("got level %v, want %v", got, lvl)
+		}
+	}
+	if _, err := goyekcolor.ParseLevel("trace"); err == nil {
+		t.Error("want error for unknown level")
+	}
+}
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		level goyekcolor.Level
+		want  string
+	}{
+		{
+			level: goyekcolor.LevelDebug,
+			want:  "      level_test.go:69: DEBUG: debug 1\n      level_test.go:70: INFO: info 2\n      level_test.go:71: WARN: warn 3\n",
+		},
+		{
+			level: goyekcolor.LevelInfo,
+			want:  "      level_test.go:70: INFO: info 2\n      level_test.go:71: WARN: warn 3\n",
+		},
+		{
+			level: goyekcolor.LevelWarn,
+			want:  "      level_test.go:71: WARN: warn 3\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			setLogLevel(t, tc.level)
+
+			got := logLevels(t, &goyekcolor.CodeLineLogger{})
+
+			if got != tc.want {
+				t.Errorf("got output %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func logLevels(t *testing.T, logger goyek.Logger) string {
+	t.Helper()
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(logger)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			goyekcolor.Debug(a, "debug ", 1)
+			goyekcolor.Infof(a, "info %d", 2)
+			goyekcolor.Warnf(a, "warn %d", 3)
+		},
+	})
+	_ = flow.Execute(context.Background(), []string{"task"})
+	return out.String()
+}
+
+func TestLevelsColors(t *testing.T) {
+	forceColor(t)
+	setLogLevel(t, goyekcolor.LevelDebug)
+
+	got := logLevels(t, &goyekcolor.CodeLineLogger{})
+
+	for _, want := range []string{
+		": \x1b[90mDEBUG: debug 1" + ansiReset + "\n",
+		": INFO: info 2\n",
+		": \x1b[93mWARN: warn 3" + ansiReset + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestLevelsWithTheme(t *testing.T) {
+	forceColor(t)
+	setLogLevel(t, goyekcolor.LevelDebug)
+	theme := goyekcolor.DefaultTheme()
+	theme.Debug = goyekcolor.NewStyle(fatihcolor.FgCyan)
+	theme.Warn = goyekcolor.NewStyle(fatihcolor.FgMagenta)
+
+	got := logLevels(t, goyekcolor.NewCodeLineLogger(goyekcolor.WithTheme(theme)))
+
+	for _, want := range []string{
+		": \x1b[36mDEBUG: debug 1" + ansiReset + "\n",
+		": \x1b[35mWARN: warn 3" + ansiReset + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestLevelsJSONLogger(t *testing.T) {
+	forceColor(t)
+	setLogLevel(t, goyekcolor.LevelDebug)
+
+	got := logLevels(t, &goyekcolor.JSONLogger{})
+
+	if strings.Contains(got, "\x1b") || strings.Contains(got, `\u001b`) {
+		t.Errorf("output %q contains escape sequences", got)
+	}
+	for _, want := range []string{
+		`"level":"debug","file":`,
+		`"msg":"debug 1"}`,
+		`"level":"info","file":`,
+		`"level":"warn","file":`,
+		`"msg":"warn 3"}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func setLogLevel(t *testing.T, lvl goyekcolor.Level) {
+	t.Helper()
+	old := goyekcolor.LogLevel()
+	goyekcolor.SetLogLevel(lvl)
+	t.Cleanup(func() {
+		goyekcolor.SetLogLevel(old)
+	})
+}
@@ -6,119 +6,188 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
 )
 
 // CodeLineLogger decorates the log with code line information, indentation and colors.
+//
+// The zero value is ready to use with the default configuration.
 type CodeLineLogger struct {
+	cfg         *config
+	cfgOnce     sync.Once
+	start       time.Time // used to print the elapsed time
 	mu          sync.Mutex
-	helperPCs   map[uintptr]struct{} // functions to be skipped when writing file/line info
-	helperNames map[string]struct{}  // helperPCs converted to function names
+	helperNames map[string]struct{} // functions to be skipped when writing file/line info
+}
+
+// NewCodeLineLogger returns a CodeLineLogger configured using the options.
+func NewCodeLineLogger(opts ...LoggerOption) *CodeLineLogger {
+	return &CodeLineLogger{cfg: newConfig(opts, LoggerOption.applyLogger), start: time.Now()}
+}
+
+// Middleware is a runner middleware, which makes the elapsed time
+// printed by the logger configured using [WithElapsed] relative
+// to the start of each task. Otherwise, it is relative to the creation
+// of the logger.
+//
+// It uses a copy of the logger for each task. Therefore, it has to be
+// used after middlewares which wrap the logger, like [Summary.Middleware].
+func (l *CodeLineLogger) Middleware(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		if in.Logger == goyek.Logger(l) {
+			in.Logger = &CodeLineLogger{cfg: l.config(), start: time.Now()}
+		}
+		return next(in)
+	}
+}
+
+func (l *CodeLineLogger) config() *config {
+	l.cfgOnce.Do(func() {
+		if l.cfg == nil {
+			l.cfg = newConfig[Option](nil, Option.apply)
+		}
+		if l.start.IsZero() {
+			l.start = time.Now()
+		}
+	})
+	return l.cfg
 }
 
 // Log is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Log(w io.Writer, args ...interface{}) {
-	txt := fmt.Sprint(args...)
-	txt = l.decorate(txt)
+	txt := l.sprint(w, args)
+	txt = l.decorate(w, txt, false)
 	io.WriteString(w, txt) //nolint:errcheck // not checking errors when writing to output
 }
 
 // Logf is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Logf(w io.Writer, format string, args ...interface{}) {
 	txt := fmt.Sprintf(format, args...)
-	txt = l.decorate(txt)
+	txt = l.decorate(w, txt, false)
 	io.WriteString(w, txt) //nolint:errcheck // not checking errors when writing to output
 }
 
 // Error is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Error(w io.Writer, args ...interface{}) {
 	txt := fmt.Sprint(args...)
-	txt = l.decorate(txt)
-	writeString(w, color.New(color.FgRed).Sprint(txt))
+	txt = l.decorate(w, txt, true)
+	writeString(w, l.config().Theme.Failed.sprint(Enabled(w), txt))
 }
 
 // Errorf is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Errorf(w io.Writer, format string, args ...interface{}) {
 	txt := fmt.Sprintf(format, args...)
-	txt = l.decorate(txt)
-	writeString(w, color.New(color.FgRed).Sprint(txt))
+	txt = l.decorate(w, txt, true)
+	writeString(w, l.config().Theme.Failed.sprint(Enabled(w), txt))
 }
 
 // Fatal is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Fatal(w io.Writer, args ...interface{}) {
 	txt := fmt.Sprint(args...)
-	txt = l.decorate(txt)
-	writeString(w, color.New(color.FgRed).Sprint(txt))
+	txt = l.decorate(w, txt, true)
+	writeString(w, l.config().Theme.Failed.sprint(Enabled(w), txt))
 }
 
 // Fatalf is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Fatalf(w io.Writer, format string, args ...interface{}) {
 	txt := fmt.Sprintf(format, args...)
-	txt = l.decorate(txt)
-	writeString(w, color.New(color.FgRed).Sprint(txt))
+	txt = l.decorate(w, txt, true)
+	writeString(w, l.config().Theme.Failed.sprint(Enabled(w), txt))
 }
 
 // Skip is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Skip(w io.Writer, args ...interface{}) {
 	txt := fmt.Sprint(args...)
-	txt = l.decorate(txt)
-	writeString(w, color.New(color.FgYellow).Sprint(txt))
+	txt = l.decorate(w, txt, false)
+	writeString(w, l.config().Theme.Skipped.sprint(Enabled(w), txt))
 }
 
 // Skipf is used internally in order to provide proper prefix.
 func (l *CodeLineLogger) Skipf(w io.Writer, format string, args ...interface{}) {
 	txt := fmt.Sprintf(format, args...)
-	txt = l.decorate(txt)
-	writeString(w, color.New(color.FgYellow).Sprint(txt))
+	txt = l.decorate(w, txt, false)
+	writeString(w, l.config().Theme.Skipped.sprint(Enabled(w), txt))
+}
+
+// themedRecord is a log record, which CodeLineLogger colors using its theme.
+// Other loggers format it like any other value.
+type themedRecord interface {
+	fmt.Stringer
+	colorize(theme Theme, colored bool) string
+}
+
+// sprint formats the arguments like fmt.Sprint
+// and colors a single themedRecord.
+func (l *CodeLineLogger) sprint(w io.Writer, args []interface{}) string {
+	if len(args) == 1 {
+		if r, ok := args[0].(themedRecord); ok {
+			return r.colorize(l.config().Theme, Enabled(w))
+		}
+	}
+	return fmt.Sprint(args...)
 }
 
 // Helper marks the calling function as a helper function.
 // When printing file and line information, that function will be skipped.
 // Helper may be called simultaneously from multiple goroutines.
 func (l *CodeLineLogger) Helper() {
-	var pc [1]uintptr
-	const skip = 3 // skip: runtime.Callers + codeLineLogger.Helper + A.Helper
+	const maxStackLen = 10
+	var pc [maxStackLen]uintptr
+	const skip = 2 // skip: runtime.Callers + codeLineLogger.Helper
 	n := runtime.Callers(skip, pc[:])
 	if n == 0 {
 		panic("zero callers found")
 	}
 
+	// The helper is the caller of A.Helper which may call CodeLineLogger
+	// through other loggers wrapping it.
+	frames := runtime.CallersFrames(pc[:n])
+	helper, _ := frames.Next()
+	for frame, more := helper, true; more; frame, more = frames.Next() {
+		if frame.Function == "github.com/goyek/goyek/v3.(*A).Helper" {
+			helper, _ = frames.Next()
+			break
+		}
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if l.helperPCs == nil {
-		l.helperPCs = make(map[uintptr]struct{})
-	}
-	if _, found := l.helperPCs[pc[0]]; !found {
-		l.helperPCs[pc[0]] = struct{}{}
-		l.helperNames = nil // map will be recreated next time it is needed
+	if l.helperNames == nil {
+		l.helperNames = make(map[string]struct{})
 	}
+	l.helperNames[helper.Function] = struct{}{}
 }
 
 // decorate prefixes the string with the file and line of the call site
 // and inserts the final newline and indentation spaces for formatting.
-func (l *CodeLineLogger) decorate(s string) string {
+// If withSource is true, the source code around the call site is appended
+// when configured using WithSourceContext.
+func (l *CodeLineLogger) decorate(w io.Writer, s string, withSource bool) string {
 	const skip = 3
 	frame := l.frameSkip(skip)
+	cfg := l.config()
 	file := frame.File
 	line := frame.Line
 	if file != "" {
-		// Truncate file name at last file name separator.
-		if index := strings.LastIndex(file, "/"); index >= 0 {
-			file = file[index+1:]
-		} else if index = strings.LastIndex(file, "\\"); index >= 0 {
-			file = file[index+1:]
-		}
+		file = cfg.PathMode.format(file)
 	} else {
 		file = "???"
 	}
 	if line == 0 {
 		line = 1
 	}
+	codeLine := fmt.Sprintf("%s:%d", file, line)
+	if cfg.Links != "" && frame.File != "" && Enabled(w) {
+		codeLine = hyperlink(cfg.Links, frame.File, line, codeLine)
+	}
 	buf := &strings.Builder{}
 	// Every line is indented at least 6 spaces.
 	buf.WriteString("      ")
-	fmt.Fprintf(buf, "%s:%d: ", file, line)
+	l.writeTime(buf, cfg)
+	buf.WriteString(codeLine)
+	buf.WriteString(": ")
 	lines := strings.Split(s, "\n")
 	if l := len(lines); l > 1 && lines[l-1] == "" {
 		lines = lines[:l-1]
@@ -130,12 +199,32 @@ func (l *CodeLineLogger) decorate(s string) string {
 		}
 		buf.WriteString(line)
 	}
+	if withSource && cfg.SourceContext > 0 && frame.File != "" {
+		writeSource(buf, frame.File, frame.Line, cfg.SourceContext, Enabled(w))
+	}
 	buf.WriteByte('\n')
 	return buf.String()
 }
 
+// writeTime writes the timestamp and the elapsed time if configured.
+func (l *CodeLineLogger) writeTime(buf *strings.Builder, cfg *config) {
+	if cfg.Timestamp == "" && !cfg.Elapsed {
+		return
+	}
+	now := time.Now()
+	if cfg.Timestamp != "" {
+		buf.WriteString(now.Format(cfg.Timestamp))
+		buf.WriteByte(' ')
+	}
+	if cfg.Elapsed {
+		fmt.Fprintf(buf, "+%.3fs ", now.Sub(l.start).Seconds())
+	}
+}
+
 // frameSkip searches, starting after skip frames, for the first caller frame
 // in a function not marked as a helper and returns that frame.
+// Frames of this package and goyek are skipped so that loggers wrapping
+// CodeLineLogger report the same call site.
 // The search stops when it reaches the goyek runner. This ensures that an
 // action which marks itself as a helper is still reported as the call site.
 func (l *CodeLineLogger) frameSkip(skip int) runtime.Frame {
@@ -159,21 +248,17 @@ func (l *CodeLineLogger) frameSkip(skip int) runtime.Frame {
 		if frame.Function == "runtime.gopanic" {
 			continue
 		}
-		if firstFrame.PC == 0 {
-			firstFrame = frame
-		}
 		if frame.Function == "github.com/goyek/goyek/v3.(*A).run.func1" {
 			// We've gone up all the way to the runner calling
 			// the action (so the user must have
 			// called a.Helper from inside that action).
 			return prevFrame
 		}
-		// If more helper PCs have been added since we last did the conversion
-		if l.helperNames == nil {
-			l.helperNames = make(map[string]struct{})
-			for pc := range l.helperPCs {
-				l.helperNames[pcToName(pc)] = struct{}{}
-			}
+		if isInternalFrame(frame) {
+			continue
+		}
+		if firstFrame.PC == 0 {
+			firstFrame = frame
 		}
 		if _, ok := l.helperNames[frame.Function]; !ok {
 			// Found a frame that wasn't inside a helper function.
@@ -183,11 +268,10 @@ func (l *CodeLineLogger) frameSkip(skip int) runtime.Frame {
 	return firstFrame
 }
 
-func pcToName(pc uintptr) string {
-	pcs := []uintptr{pc}
-	frames := runtime.CallersFrames(pcs)
-	frame, _ := frames.Next()
-	return frame.Function
+// isInternalFrame reports whether the frame belongs to this package or goyek.
+func isInternalFrame(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, "github.com/goyek/x/color.") ||
+		strings.HasPrefix(frame.Function, "github.com/goyek/goyek/v3.")
 }
 
 // writeString keeps each formatted record within one call to the destination.
@@ -0,0 +1,152 @@
+package color
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// stepSummaryEnv is the environment variable containing the path
+// of the GitHub Actions job summary file.
+const stepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// statusEmoji maps the task status names to emoji.
+var statusEmoji = map[string]string{
+	"PASS": "✅",
+	"FAIL": "❌",
+	"SKIP": "⏭️",
+	"NOOP": "⚪",
+}
+
+// Markdown appends a Markdown report of the flow execution to a file,
+// like the GitHub Actions job summary. The report contains a table
+// of the tasks with their status and duration, followed by
+// the collapsible output of the failed tasks. Long output is shortened
+// to its beginning and end.
+//
+// If no file is specified, the file from the GITHUB_STEP_SUMMARY
+// environment variable is used. No report is written if it is not set.
+//
+// Both [Markdown.Middleware] and [Markdown.ExecutorMiddleware]
+// have to be used. The runner middleware records the tasks and
+// their output, and the executor middleware writes the report
+// after the flow execution.
+type Markdown struct {
+	path string
+	cfg  *config
+	rec  recorder
+}
+
+// NewMarkdown returns a Markdown appending the report to the file
+// at the path and configured using the options.
+func NewMarkdown(path string, opts ...Option) *Markdown {
+	return &Markdown{
+		path: path,
+		cfg:  newConfig(opts, Option.apply),
+		rec:  recorder{captureOutput: true},
+	}
+}
+
+// Middleware is a runner middleware, which records the outcome
+// and the output of each task.
+func (m *Markdown) Middleware(next goyek.Runner) goyek.Runner {
+	return m.rec.middleware(next)
+}
+
+// ExecutorMiddleware is an executor middleware, which writes the report
+// after the flow execution. Problems with writing the report are printed
+// to the output.
+func (m *Markdown) ExecutorMiddleware(next goyek.Executor) goyek.Executor {
+	return func(in goyek.ExecuteInput) error {
+		out := outputOrDiscard(in.Output)
+		in.Output = out
+
+		m.rec.reset()
+		err := next(in)
+
+		path := m.path
+		if path == "" {
+			path = os.Getenv(stepSummaryEnv)
+		}
+		if path == "" {
+			return err
+		}
+		if werr := appendFile(path, m.format(err)); werr != nil {
+			writeString(out, fmt.Sprintf("markdown report: %v\n", werr))
+		}
+		return err
+	}
+}
+
+func (m *Markdown) format(flowErr error) string {
+	sb := &strings.Builder{}
+	if flowErr != nil {
+		fmt.Fprintf(sb, "### %s goyek: %s\n\n", statusEmoji["FAIL"], escapeMarkdown(firstLine(flowErr.Error())))
+	} else {
+		fmt.Fprintf(sb, "### %s goyek: ok\n\n", statusEmoji["PASS"])
+	}
+
+	records := m.rec.taskRecords()
+	if len(records) > 0 {
+		sb.WriteString("| Task | Status | Duration |\n")
+		sb.WriteString("| --- | --- | ---: |\n")
+		for _, rec := range records {
+			status, _ := m.cfg.Theme.status(rec.Status)
+			fmt.Fprintf(sb, "| %s | %s %s | %.2fs |\n",
+				escapeMarkdown(rec.Name), statusEmoji[status], status, rec.Duration.Seconds())
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, rec := range records {
+		if rec.Status != goyek.StatusFailed {
+			continue
+		}
+		output := strings.TrimRight(stripANSI(rec.Output), "\n")
+		fence := codeFence(output)
+		fmt.Fprintf(sb, "<details><summary>%s %s</summary>\n\n", statusEmoji["FAIL"], escapeHTML(rec.Name))
+		fmt.Fprintf(sb, "%stext\n%s\n%s\n\n</details>\n\n", fence, output, fence)
+	}
+	return sb.String()
+}
+
+// appendFile appends the text to the file creating it if necessary.
+func appendFile(path, s string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // writing the configured file is intended
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(s); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// codeFence returns a code fence longer than any backtick sequence
+// in the text.
+func codeFence(s string) string {
+	longest, n := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			n++
+			longest = max(longest, n)
+		} else {
+			n = 0
+		}
+	}
+	const minFence = 3
+	return strings.Repeat("`", max(longest+1, minFence))
+}
+
+// escapeMarkdown escapes the text used in a table cell or a heading.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;", "*", `\*`, "_", `\_`, "`", "\\`").Replace(s)
+}
+
+// escapeHTML escapes the text used in an HTML element.
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,162 @@
+package color_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestMarkdown(t *testing.T) {
+	forceColor(t)
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+	if err := os.WriteFile(path, []byte("previous\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	report := goyekcolor.NewMarkdown("")
+	flow := &goyek.Flow{}
+	flow.SetOutput(&strings.Builder{})
+	flow.SetLogger(&goyekcolor.CodeLineLogger{})
+	flow.Use(report.Middleware)
+	flow.UseExecutor(report.ExecutorMiddleware)
+	skip := flow.Define(goyek.Task{
+		Name: "skip",
+		Action: func(a *goyek.A) {
+			a.Skip("not needed")
+		},
+	})
+	flow.Define(goyek.Task{
+		Name: "a|b",
+		Deps: goyek.Deps{skip},
+		Action: func(a *goyek.A) {
+			fmt.Fprintln(a.Output(), "```go")
+			a.Error("failure")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"a|b"})
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The error message of the flow depends on goyek.
+	text, table, _ := strings.Cut(string(got), "\n\n")
+	if !strings.HasPrefix(text, "previous\n### ❌ goyek: ") {
+		t.Errorf("unexpected report heading: %q", text)
+	}
+	want := "| Task | Status | Duration |\n" +
+		"| --- | --- | ---: |\n" +
+		"| skip | ⏭️ SKIP | 0.00s |\n" +
+		"| a\\|b | ❌ FAIL | 0.00s |\n\n" +
+		"<details><summary>❌ a|b</summary>\n\n" +
+		"````text\n" +
+		"```go\n" +
+		"      markdown_test.go:41: failure\n" +
+		"````\n\n" +
+		"</details>\n\n"
+	if table != want {
+		t.Errorf("got report:\n%s\nwant:\n%s", table, want)
+	}
+}
+
+func TestMarkdownTruncatedOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	report := goyekcolor.NewMarkdown("")
+	flow := &goyek.Flow{}
+	flow.SetOutput(&strings.Builder{})
+	flow.Use(report.Middleware)
+	flow.UseExecutor(report.ExecutorMiddleware)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			for i := range 2000 {
+				fmt.Fprintf(a.Output(), "line %04d %s\n", i, strings.Repeat(".", 90))
+			}
+			a.Error("failure at the end")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(got)
+	for _, want := range []string{"\nline 0000 ", "\n... (truncated)\nline ", "failure at the end\n```\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(text, "line 1000 ") {
+		t.Error("report contains the middle of the output")
+	}
+	if len(text) > 70<<10 {
+		t.Errorf("report has %d bytes", len(text))
+	}
+}
+
+func TestMarkdownFile(t *testing.T) {
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	path := filepath.Join(t.TempDir(), "report.md")
+
+	report := goyekcolor.NewMarkdown(path)
+	executor := report.ExecutorMiddleware(func(in goyek.ExecuteInput) error {
+		runner := report.Middleware(func(goyek.Input) goyek.Result {
+			return goyek.Result{Status: goyek.StatusPassed}
+		})
+		runner(goyek.Input{Context: in.Context, TaskName: "task", Output: in.Output})
+		return nil
+	})
+
+	_ = executor(goyek.ExecuteInput{Context: context.Background(), Output: &strings.Builder{}})
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "### ✅ goyek: ok\n\n") || !strings.Contains(string(got), "| task | ✅ PASS | ") ||
+		strings.Contains(string(got), "<details>") {
+		t.Errorf("unexpected report:\n%s", got)
+	}
+}
+
+func TestMarkdownNoFile(t *testing.T) {
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	out := &strings.Builder{}
+
+	report := goyekcolor.NewMarkdown("")
+	executor := report.ExecutorMiddleware(func(goyek.ExecuteInput) error { return nil })
+
+	if err := executor(goyek.ExecuteInput{Output: out}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestMarkdownWriteError(t *testing.T) {
+	out := &strings.Builder{}
+
+	report := goyekcolor.NewMarkdown(t.TempDir())
+	executor := report.ExecutorMiddleware(func(goyek.ExecuteInput) error { return nil })
+
+	if err := executor(goyek.ExecuteInput{Output: out}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "markdown report: ") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
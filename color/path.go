@@ -0,0 +1,101 @@
+package color
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PathMode defines how the logger prints the file path of the call site.
+type PathMode int
+
+// Path modes.
+const (
+	PathBase     PathMode = iota // the file name, e.g. helper.go
+	PathModule                   // the path relative to the module root, e.g. build/helper.go
+	PathAbsolute                 // the absolute path
+)
+
+// format returns the file path as defined by the mode.
+func (m PathMode) format(file string) string {
+	switch m {
+	case PathAbsolute:
+		return file
+	case PathModule:
+		if root, ok := moduleRoot(filepath.Dir(file)); ok {
+			if rel, err := filepath.Rel(root, file); err == nil {
+				return filepath.ToSlash(rel)
+			}
+		}
+		return file
+	default:
+		// Truncate file name at last file name separator.
+		if index := strings.LastIndex(file, "/"); index >= 0 {
+			return file[index+1:]
+		} else if index = strings.LastIndex(file, "\\"); index >= 0 {
+			return file[index+1:]
+		}
+		return file
+	}
+}
+
+// moduleRoots caches the module root directories by the source directory.
+var moduleRoots sync.Map
+
+// moduleRoot returns the directory containing the go.mod file
+// of the module containing the directory.
+func moduleRoot(dir string) (string, bool) {
+	if v, ok := moduleRoots.Load(dir); ok {
+		root := v.(string)
+		return root, root != ""
+	}
+	root := ""
+	if filepath.IsAbs(dir) {
+		for d := dir; ; {
+			if fi, err := os.Stat(filepath.Join(d, "go.mod")); err == nil && fi.Mode().IsRegular() {
+				root = d
+				break
+			}
+			parent := filepath.Dir(d)
+			if parent == d {
+				break
+			}
+			d = parent
+		}
+	}
+	moduleRoots.Store(dir, root)
+	return root, root != ""
+}
+
+// hyperlink returns the text as an OSC 8 hyperlink to the file and line.
+// The link is a file URL if the template is "file". Otherwise, {path} and
+// {line} in the template are replaced with the escaped file path and line number.
+func hyperlink(template, file string, line int, text string) string {
+	var link string
+	if template == "file" {
+		if !filepath.IsAbs(file) {
+			return text
+		}
+		path := filepath.ToSlash(file)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path // Windows drive letter
+		}
+		link = (&url.URL{Scheme: "file", Path: path}).String()
+	} else {
+		link = strings.NewReplacer("{path}", escapePath(file), "{line}", strconv.Itoa(line)).Replace(template)
+	}
+	return "\x1b]8;;" + link + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// escapePath returns the slash-separated file path
+// with each element escaped for use in a URL.
+func escapePath(file string) string {
+	elems := strings.Split(filepath.ToSlash(file), "/")
+	for i, elem := range elems {
+		elems[i] = url.PathEscape(elem)
+	}
+	return strings.Join(elems, "/")
+}
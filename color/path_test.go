@@ -0,0 +1,97 @@
+package color_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestCodeLineLoggerPathMode(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []goyekcolor.LoggerOption
+		want string
+	}{
+		{name: "default", want: "      path_test.go:"},
+		{name: "base", opts: []goyekcolor.LoggerOption{goyekcolor.WithPathMode(goyekcolor.PathBase)}, want: "      path_test.go:"},
+		{name: "module", opts: []goyekcolor.LoggerOption{goyekcolor.WithPathMode(goyekcolor.PathModule)}, want: "      color/path_test.go:"},
+		{name: "absolute", opts: []goyekcolor.LoggerOption{goyekcolor.WithPathMode(goyekcolor.PathAbsolute)}, want: "/color/path_test.go:"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := logMessage(t, goyekcolor.NewCodeLineLogger(tc.opts...))
+
+			if !strings.Contains(got, tc.want) || !strings.HasSuffix(got, ": message\n") {
+				t.Errorf("output %q does not contain %q", got, tc.want)
+			}
+			if tc.name == "absolute" && strings.HasPrefix(got, "      color/") {
+				t.Errorf("output %q does not contain an absolute path", got)
+			}
+		})
+	}
+}
+
+func TestCodeLineLoggerLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "file", template: "file", want: "\x1b]8;;file:///"},
+		{name: "template", template: "vscode://file/{path}:{line}", want: "\x1b]8;;vscode://file/"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			forceColor(t)
+
+			got := logMessage(t, goyekcolor.NewCodeLineLogger(goyekcolor.WithLinks(tc.template)))
+
+			if !strings.HasPrefix(got, "      "+tc.want) {
+				t.Errorf("output %q does not start with %q", got, tc.want)
+			}
+			if !strings.Contains(got, "/color/path_test.go") || !strings.Contains(got, "\x1b\\path_test.go:") ||
+				!strings.HasSuffix(got, "\x1b]8;;\x1b\\: message\n") {
+				t.Errorf("unexpected output: %q", got)
+			}
+		})
+	}
+}
+
+func TestHyperlinkEscapedPath(t *testing.T) {
+	got := goyekcolor.Hyperlink("vscode://file/{path}:{line}", "/src/my app/#1?.go", 7, "text")
+
+	want := "\x1b]8;;vscode://file//src/my%20app/%231%3F.go:7\x1b\\text\x1b]8;;\x1b\\"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCodeLineLoggerLinksNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	got := logMessage(t, goyekcolor.NewCodeLineLogger(goyekcolor.WithLinks("file")))
+
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("output %q contains escape sequences", got)
+	}
+}
+
+func logMessage(t *testing.T, logger goyek.Logger) string {
+	t.Helper()
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(logger)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Log("message")
+		},
+	})
+	_ = flow.Execute(context.Background(), []string{"task"})
+	return out.String()
+}
@@ -0,0 +1,106 @@
+package color
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
+)
+
+// prefixPalette contains the colors of the task name prefixes.
+// Red is not used so that the prefixes are not confused with errors.
+var prefixPalette = []color.Attribute{
+	color.FgCyan,
+	color.FgMagenta,
+	color.FgBlue,
+	color.FgYellow,
+	color.FgGreen,
+	color.FgHiCyan,
+	color.FgHiMagenta,
+	color.FgHiBlue,
+	color.FgHiYellow,
+	color.FgHiGreen,
+}
+
+// PrefixParallel is a runner middleware, which streams the output
+// of parallel tasks immediately, prefixing each line with the task name.
+// The prefix color is derived from the task name, so it is the same
+// in every run. The output is line-buffered so that the lines
+// of concurrently running tasks do not interleave.
+//
+// It is an alternative to middleware.BufferParallel.
+// The output of tasks which are not parallel is not changed.
+func PrefixParallel(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		if !in.Parallel {
+			return next(in)
+		}
+		out := outputOrDiscard(in.Output)
+		style := NewStyle(prefixColor(in.TaskName))
+		pw := &prefixWriter{
+			w:      out,
+			prefix: []byte(style.sprint(Enabled(out), "["+in.TaskName+"]") + " "),
+		}
+		in.Output = pw
+
+		res := next(in)
+
+		pw.flush()
+		return res
+	}
+}
+
+// prefixColor returns the color of the task name prefix.
+func prefixColor(taskName string) color.Attribute {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(taskName))
+	return prefixPalette[h.Sum32()%uint32(len(prefixPalette))] //nolint:gosec // the palette is small
+}
+
+// prefixWriter writes each complete line with the prefix.
+type prefixWriter struct {
+	w      io.Writer
+	prefix []byte
+
+	mu      sync.Mutex
+	partial []byte // incomplete line
+}
+
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	pw.partial = append(pw.partial, p...)
+	i := bytes.LastIndexByte(pw.partial, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	buf := &bytes.Buffer{}
+	for _, line := range bytes.SplitAfter(pw.partial[:i+1], []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		buf.Write(pw.prefix)
+		buf.Write(line)
+	}
+	pw.partial = append([]byte(nil), pw.partial[i+1:]...)
+	if _, err := pw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush writes the incomplete line.
+func (pw *prefixWriter) flush() {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if len(pw.partial) == 0 {
+		return
+	}
+	buf := append(append([]byte(nil), pw.prefix...), pw.partial...)
+	buf = append(buf, '\n')
+	pw.partial = nil
+	_, _ = pw.w.Write(buf)
+}
@@ -0,0 +1,98 @@
+package color_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestPrefixParallel(t *testing.T) {
+	sb := &strings.Builder{}
+	out := goyek.SyncWriter(sb)
+	runner := goyekcolor.PrefixParallel(func(in goyek.Input) goyek.Result {
+		for i := range 100 {
+			// Write each line in two parts to check the line buffering.
+			_, _ = fmt.Fprintf(in.Output, "%s line", in.TaskName)
+			_, _ = fmt.Fprintf(in.Output, " %d\n", i)
+		}
+		_, _ = io.WriteString(in.Output, "last")
+		return goyek.Result{Status: goyek.StatusPassed}
+	})
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"first", "second"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runner(goyek.Input{Output: out, TaskName: name, Parallel: true})
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	if len(lines) != 202 {
+		t.Fatalf("got %d lines, want 202:\n%s", len(lines), sb.String())
+	}
+	counts := map[string]int{}
+	for _, line := range lines {
+		for _, name := range []string{"first", "second"} {
+			if strings.HasPrefix(line, "["+name+"] ") {
+				counts[name]++
+				if rest := strings.TrimPrefix(line, "["+name+"] "); rest != "last" &&
+					!strings.HasPrefix(rest, name+" line ") {
+					t.Errorf("interleaved line: %q", line)
+				}
+			}
+		}
+	}
+	if counts["first"] != 101 || counts["second"] != 101 {
+		t.Errorf("unexpected line counts: %v", counts)
+	}
+}
+
+func TestPrefixParallelColors(t *testing.T) {
+	forceColor(t)
+
+	got := map[string]string{}
+	for _, name := range []string{"first", "second", "first"} {
+		out := &strings.Builder{}
+		runner := goyekcolor.PrefixParallel(func(in goyek.Input) goyek.Result {
+			_, _ = io.WriteString(in.Output, "text\n")
+			return goyek.Result{}
+		})
+		runner(goyek.Input{Output: out, TaskName: name, Parallel: true})
+
+		if prev, ok := got[name]; ok && prev != out.String() {
+			t.Errorf("prefix of %q is not stable: %q and %q", name, prev, out.String())
+		}
+		got[name] = out.String()
+	}
+	for name, output := range got {
+		if !strings.HasPrefix(output, "\x1b[") || !strings.HasSuffix(output, "["+name+"]"+ansiReset+" text\n") {
+			t.Errorf("unexpected output: %q", output)
+		}
+	}
+	if got["first"] == got["second"] {
+		t.Errorf("same prefix colors: %q", got["first"])
+	}
+}
+
+func TestPrefixParallelNotParallel(t *testing.T) {
+	out := &strings.Builder{}
+	runner := goyekcolor.PrefixParallel(func(in goyek.Input) goyek.Result {
+		_, _ = io.WriteString(in.Output, "text\n")
+		return goyek.Result{}
+	})
+
+	runner(goyek.Input{Output: out, TaskName: "task"})
+
+	if got, want := out.String(), "text\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,315 @@
+package color
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// progressInterval is how often the live status area is refreshed.
+const progressInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Progress displays a live status area below the output when it is written
+// to a terminal. The area shows a spinner with the elapsed time for each
+// running task and the count of done and pending tasks.
+// When the output is not a terminal, the output is not changed.
+//
+// Both [Progress.Middleware] and [Progress.ExecutorMiddleware]
+// have to be used with the flow. The lines of the area are truncated
+// to the width of the terminal.
+type Progress struct {
+	cfg      *config
+	flow     *goyek.Flow
+	terminal func(out io.Writer) (width int, ok bool)
+
+	mu   sync.Mutex
+	live *liveWriter // set during the flow execution on a terminal
+}
+
+// NewProgress returns a Progress for the flow configured using the options.
+// The flow is used to count the tasks to be run.
+// If the flow is nil, only the count of done tasks is shown.
+func NewProgress(flow *goyek.Flow, opts ...Option) *Progress {
+	p := &Progress{cfg: newConfig(opts, Option.apply), flow: flow}
+	p.terminal = p.detectTerminal
+	return p
+}
+
+// Middleware is a runner middleware, which tracks the running tasks.
+func (p *Progress) Middleware(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		p.mu.Lock()
+		live := p.live
+		p.mu.Unlock()
+		if live == nil {
+			return next(in)
+		}
+
+		live.taskStarted(in.TaskName)
+		res := next(in)
+		live.taskFinished(in.TaskName)
+		return res
+	}
+}
+
+// ExecutorMiddleware is an executor middleware, which displays the live
+// status area during the flow execution.
+func (p *Progress) ExecutorMiddleware(next goyek.Executor) goyek.Executor {
+	return func(in goyek.ExecuteInput) error {
+		out := outputOrDiscard(in.Output)
+		in.Output = out
+		if _, ok := p.terminal(out); !ok {
+			return next(in)
+		}
+
+		live := &liveWriter{
+			w:       out,
+			width:   func() int { width, _ := p.terminal(out); return width },
+			theme:   p.cfg.Theme,
+			colored: Enabled(out),
+			total:   taskCount(p.flow, in),
+			start:   time.Now(),
+		}
+		p.mu.Lock()
+		p.live = live
+		p.mu.Unlock()
+		defer func() {
+			p.mu.Lock()
+			p.live = nil
+			p.mu.Unlock()
+		}()
+
+		in.Output = live
+		stop := live.run(progressInterval)
+		defer stop()
+		return next(in)
+	}
+}
+
+// detectTerminal reports whether the output is a terminal supporting
+// cursor movement and returns its width. Outputs which cannot
+// be unwrapped, like the synchronized output of the flow,
+// are assumed to end up in the output of the flow.
+func (p *Progress) detectTerminal(out io.Writer) (int, bool) {
+	if os.Getenv("TERM") == "dumb" {
+		return 0, false
+	}
+	fd, ok := fileDescriptor(out)
+	if !ok && p.flow != nil {
+		fd, ok = fileDescriptor(p.flow.Output())
+	}
+	if !ok || !isTerminal(fd) {
+		return 0, false
+	}
+	width, ok := terminalWidth(fd)
+	if !ok {
+		width = defaultTerminalWidth
+	}
+	return width, true
+}
+
+// defaultTerminalWidth is used when the terminal width cannot be determined.
+const defaultTerminalWidth = 80
+
+// taskCount returns the number of tasks to be run by the flow execution
+// or 0 if the flow is not known.
+func taskCount(flow *goyek.Flow, in goyek.ExecuteInput) int {
+	if flow == nil {
+		return 0
+	}
+	tasks := map[string]*goyek.DefinedTask{}
+	for _, task := range flow.Tasks() {
+		tasks[task.Name()] = task
+	}
+	seen := map[string]bool{}
+	var visit func(task *goyek.DefinedTask)
+	visit = func(task *goyek.DefinedTask) {
+		if seen[task.Name()] {
+			return
+		}
+		seen[task.Name()] = true
+		if in.NoDeps {
+			return
+		}
+		for _, dep := range task.Deps() {
+			visit(dep)
+		}
+	}
+	for _, name := range in.Tasks {
+		if task, ok := tasks[name]; ok {
+			visit(task)
+		}
+	}
+	for _, name := range in.SkipTasks {
+		delete(seen, name)
+	}
+	return len(seen)
+}
+
+// liveWriter writes complete lines above the live status area.
+type liveWriter struct {
+	w       io.Writer
+	width   func() int // number of columns of the terminal
+	theme   Theme
+	colored bool
+	total   int
+	start   time.Time
+
+	mu      sync.Mutex
+	running []runningTask
+	done    int
+	frame   int
+	partial []byte // incomplete line
+	lines   int    // number of lines of the displayed area
+}
+
+type runningTask struct {
+	name  string
+	start time.Time
+}
+
+func (lw *liveWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	data := append(lw.partial, p...)
+	i := bytes.LastIndexByte(data, '\n')
+	if i < 0 {
+		lw.partial = data
+		return len(p), nil
+	}
+	lw.partial = append([]byte(nil), data[i+1:]...)
+
+	buf := &bytes.Buffer{}
+	lw.clear(buf)
+	buf.Write(data[:i+1])
+	lw.draw(buf)
+	if _, err := lw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (lw *liveWriter) taskStarted(name string) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	lw.running = append(lw.running, runningTask{name: name, start: time.Now()})
+	lw.refresh()
+}
+
+func (lw *liveWriter) taskFinished(name string) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	for i, task := range lw.running {
+		if task.name == name {
+			lw.running = append(lw.running[:i], lw.running[i+1:]...)
+			break
+		}
+	}
+	lw.done++
+	lw.refresh()
+}
+
+// run refreshes the area periodically until the returned function is called.
+// Afterwards, the area is removed and the incomplete line is written.
+func (lw *liveWriter) run(interval time.Duration) func() {
+	lw.mu.Lock()
+	lw.refresh()
+	lw.mu.Unlock()
+
+	stopCh := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				lw.mu.Lock()
+				lw.frame++
+				lw.refresh()
+				lw.mu.Unlock()
+			}
+		}
+	}()
+	return func() {
+		close(stopCh)
+		<-stopped
+
+		lw.mu.Lock()
+		defer lw.mu.Unlock()
+		buf := &bytes.Buffer{}
+		lw.clear(buf)
+		buf.Write(lw.partial)
+		lw.partial = nil
+		_, _ = lw.w.Write(buf.Bytes())
+	}
+}
+
+// refresh redraws the area. It must be called with the lock held.
+func (lw *liveWriter) refresh() {
+	buf := &bytes.Buffer{}
+	lw.clear(buf)
+	lw.draw(buf)
+	_, _ = lw.w.Write(buf.Bytes())
+}
+
+// clear removes the displayed area.
+func (lw *liveWriter) clear(buf *bytes.Buffer) {
+	if lw.lines > 0 {
+		fmt.Fprintf(buf, "\x1b[%dA\x1b[J", lw.lines)
+	}
+	lw.lines = 0
+}
+
+// draw writes the area. Each line is truncated to the terminal width
+// so that it takes exactly one line on the screen.
+func (lw *liveWriter) draw(buf *bytes.Buffer) {
+	now := time.Now()
+	width := lw.width()
+	spinner := lw.theme.Task.sprint(lw.colored, spinnerFrames[lw.frame%len(spinnerFrames)])
+	const spinnerWidth = 2 // the spinner and the space
+	for _, task := range lw.running {
+		line := fmt.Sprintf("%s (%.1fs)", task.name, now.Sub(task.start).Seconds())
+		fmt.Fprintf(buf, "%s %s\n", spinner, truncate(line, width-spinnerWidth))
+		lw.lines++
+	}
+
+	status := &strings.Builder{}
+	if lw.total > 0 {
+		pending := max(lw.total-lw.done-len(lw.running), 0)
+		fmt.Fprintf(status, "%d/%d done, %d pending", lw.done, lw.total, pending)
+	} else {
+		fmt.Fprintf(status, "%d done", lw.done)
+	}
+	fmt.Fprintf(status, " (%.1fs)", now.Sub(lw.start).Seconds())
+	buf.WriteString(truncate(status.String(), width))
+	buf.WriteByte('\n')
+	lw.lines++
+}
+
+// truncate shortens the text so that it fits in the width without
+// reaching the last column, which would wrap the line on some terminals.
+func truncate(s string, width int) string {
+	limit := width - 1
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	if limit <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:limit-1]) + "…"
+}
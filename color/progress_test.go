@@ -0,0 +1,141 @@
+package color_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestProgress(t *testing.T) {
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	progress := goyekcolor.NewProgress(flow)
+	progress.SetTerminal(true, 80)
+	flow.Use(progress.Middleware)
+	flow.UseExecutor(progress.ExecutorMiddleware)
+	first := flow.Define(goyek.Task{
+		Name: "first",
+		Action: func(a *goyek.A) {
+			_, _ = io.WriteString(a.Output(), "first output\n")
+			time.Sleep(150 * time.Millisecond)
+		},
+	})
+	flow.Define(goyek.Task{
+		Name: "second",
+		Deps: goyek.Deps{first},
+		Action: func(a *goyek.A) {
+			_, _ = io.WriteString(a.Output(), "partial")
+		},
+	})
+	flow.Define(goyek.Task{
+		Name: "other",
+	})
+
+	if err := flow.Execute(context.Background(), []string{"second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"0/2 done, 2 pending (",
+		" first (0.0s)\n0/2 done, 1 pending (",
+		"\x1b[2A\x1b[Jfirst output\n",
+		" second (0.0s)\n1/2 done, 0 pending (",
+		"2/2 done, 0 pending (",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+	if want := "\x1b[1A\x1b[Jpartial"; !strings.HasSuffix(got, want) {
+		t.Errorf("output %q does not end with %q", got, want)
+	}
+}
+
+func TestProgressNilFlow(t *testing.T) {
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	progress := goyekcolor.NewProgress(nil)
+	progress.SetTerminal(true, 80)
+	flow.Use(progress.Middleware)
+	flow.UseExecutor(progress.ExecutorMiddleware)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			_, _ = io.WriteString(a.Output(), "output\n")
+		},
+	})
+
+	if err := flow.Execute(context.Background(), []string{"task"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"output\n",
+		" task (0.0s)\n0 done (",
+		"\x1b[J1 done (",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestProgressNotTerminal(t *testing.T) {
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	progress := goyekcolor.NewProgress(flow)
+	flow.Use(progress.Middleware)
+	flow.UseExecutor(progress.ExecutorMiddleware)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			_, _ = io.WriteString(a.Output(), "output\n")
+		},
+	})
+
+	if err := flow.Execute(context.Background(), []string{"task"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := out.String(), "output\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+}
+
+func TestProgressTruncated(t *testing.T) {
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	progress := goyekcolor.NewProgress(flow)
+	progress.SetTerminal(true, 12)
+	flow.Use(progress.Middleware)
+	flow.UseExecutor(progress.ExecutorMiddleware)
+	flow.Define(goyek.Task{
+		Name: "very-long-task-name",
+	})
+
+	if err := flow.Execute(context.Background(), []string{"very-long-task-name"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"⠋ very-lon…\n",
+		"0/1 done, …\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package color
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// taskRecord is the outcome of a task run.
+type taskRecord struct {
+	Name     string
+	Status   goyek.Status
+	Duration time.Duration
+	Message  string // first line of the first error or skip message
+	Output   string // captured output if enabled
+}
+
+// outputLimit is the maximum size of the captured output of a task.
+const outputLimit = 64 << 10
+
+// recorder records the outcomes of the tasks run by a flow.
+type recorder struct {
+	captureOutput bool
+
+	mu      sync.Mutex
+	records []taskRecord
+}
+
+// middleware records the outcome of each task.
+func (r *recorder) middleware(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		logger := &recordingLogger{Logger: in.Logger}
+		if logger.Logger == nil {
+			logger.Logger = goyek.FmtLogger{}
+		}
+		in.Logger = logger
+		var output *limitedBuffer
+		if r.captureOutput {
+			output = &limitedBuffer{limit: outputLimit}
+			in.Output = goyek.SyncWriter(io.MultiWriter(outputOrDiscard(in.Output), output))
+		}
+
+		start := time.Now()
+		res := next(in)
+		rec := taskRecord{
+			Name:     in.TaskName,
+			Status:   res.Status,
+			Duration: time.Since(start),
+			Message:  logger.message(),
+		}
+		if output != nil {
+			rec.Output = output.String()
+		}
+		if res.PanicStack != nil && rec.Message == "" {
+			rec.Message = "panic(nil) or runtime.Goexit() called"
+			if res.PanicValue != nil {
+				rec.Message = firstLine(fmt.Sprintf("panic: %v", res.PanicValue))
+			}
+		}
+
+		r.mu.Lock()
+		r.records = append(r.records, rec)
+		r.mu.Unlock()
+		return res
+	}
+}
+
+// reset removes the records of the previous flow execution.
+func (r *recorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = nil
+}
+
+// taskRecords returns the records in the order the tasks finished.
+func (r *recorder) taskRecords() []taskRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]taskRecord(nil), r.records...)
+}
+
+// limitedBuffer keeps the beginning and the end of the written data,
+// each up to half of the limit, as the errors are usually reported
+// at the end of the output.
+type limitedBuffer struct {
+	limit   int
+	head    []byte
+	tail    []byte // may hold up to the limit before it is shortened
+	dropped bool   // whether some data between the head and the tail was dropped
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	half := b.limit / 2
+	if k := min(half-len(b.head), len(p)); k > 0 {
+		b.head = append(b.head, p[:k]...)
+		p = p[k:]
+	}
+	b.tail = append(b.tail, p...)
+	if len(b.tail) > 2*half {
+		b.tail = append(b.tail[:0], b.tail[len(b.tail)-half:]...)
+		b.dropped = true
+	}
+	return n, nil
+}
+
+func (b *limitedBuffer) String() string {
+	tail := b.tail
+	if len(tail) > b.limit/2 {
+		tail = tail[len(tail)-b.limit/2:]
+	} else if !b.dropped {
+		return string(b.head) + string(tail)
+	}
+	// The tail starts with the first complete line if there is one.
+	if i := bytes.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+	return string(b.head) + "\n... (truncated)\n" + string(tail)
+}
+
+// recordingLogger passes the logs to the wrapped logger
+// and keeps the first error or skip message.
+type recordingLogger struct {
+	goyek.Logger
+
+	mu  sync.Mutex
+	msg string
+}
+
+func (l *recordingLogger) record(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.msg == "" {
+		l.msg = firstLine(msg)
+	}
+}
+
+func (l *recordingLogger) message() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.msg
+}
+
+// Error is used internally in order to record the message.
+func (l *recordingLogger) Error(w io.Writer, args ...interface{}) {
+	l.record(fmt.Sprint(args...))
+	if logger, ok := l.Logger.(interface {
+		Error(io.Writer, ...interface{})
+	}); ok {
+		logger.Error(w, args...)
+		return
+	}
+	l.Log(w, args...)
+}
+
+// Errorf is used internally in order to record the message.
+func (l *recordingLogger) Errorf(w io.Writer, format string, args ...interface{}) {
+	l.record(fmt.Sprintf(format, args...))
+	if logger, ok := l.Logger.(interface {
+		Errorf(io.Writer, string, ...interface{})
+	}); ok {
+		logger.Errorf(w, format, args...)
+		return
+	}
+	l.Logf(w, format, args...)
+}
+
+// Fatal is used internally in order to record the message.
+func (l *recordingLogger) Fatal(w io.Writer, args ...interface{}) {
+	l.record(fmt.Sprint(args...))
+	if logger, ok := l.Logger.(interface {
+		Fatal(io.Writer, ...interface{})
+	}); ok {
+		logger.Fatal(w, args...)
+		return
+	}
+	l.Log(w, args...)
+}
+
+// Fatalf is used internally in order to record the message.
+func (l *recordingLogger) Fatalf(w io.Writer, format string, args ...interface{}) {
+	l.record(fmt.Sprintf(format, args...))
+	if logger, ok := l.Logger.(interface {
+		Fatalf(io.Writer, string, ...interface{})
+	}); ok {
+		logger.Fatalf(w, format, args...)
+		return
+	}
+	l.Logf(w, format, args...)
+}
+
+// Skip is used internally in order to record the message.
+func (l *recordingLogger) Skip(w io.Writer, args ...interface{}) {
+	l.record(fmt.Sprint(args...))
+	if logger, ok := l.Logger.(interface {
+		Skip(io.Writer, ...interface{})
+	}); ok {
+		logger.Skip(w, args...)
+		return
+	}
+	l.Log(w, args...)
+}
+
+// Skipf is used internally in order to record the message.
+func (l *recordingLogger) Skipf(w io.Writer, format string, args ...interface{}) {
+	l.record(fmt.Sprintf(format, args...))
+	if logger, ok := l.Logger.(interface {
+		Skipf(io.Writer, string, ...interface{})
+	}); ok {
+		logger.Skipf(w, format, args...)
+		return
+	}
+	l.Logf(w, format, args...)
+}
+
+// Helper is used internally in order to mark the helper functions.
+func (l *recordingLogger) Helper() {
+	if logger, ok := l.Logger.(interface{ Helper() }); ok {
+		logger.Helper()
+	}
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimLeft(s, "\n"), "\n")
+	return s
+}
@@ -11,20 +11,29 @@ import (
 //
 // The format is based on the reports provided by the Go test runner.
 func ReportFlow(next goyek.Executor) goyek.Executor {
-	return func(in goyek.ExecuteInput) error {
-		out := outputOrDiscard(in.Output)
-		in.Output = out
-		c := color.New(color.Bold)
+	return NewReportFlow()(next)
+}
 
-		from := time.Now()
-		if err := next(in); err != nil {
-			c = c.Add(color.FgRed)
-			writeString(out, c.Sprintf("%v\t%.3fs\n", err, time.Since(from).Seconds()))
-			return err
-		}
+// NewReportFlow returns a middleware like [ReportFlow]
+// configured using the options.
+func NewReportFlow(opts ...Option) goyek.ExecutorMiddleware {
+	cfg := newConfig(opts, Option.apply)
+	return func(next goyek.Executor) goyek.Executor {
+		return func(in goyek.ExecuteInput) error {
+			out := outputOrDiscard(in.Output)
+			in.Output = out
+			colored := Enabled(out)
 
-		c = c.Add(color.FgGreen)
-		writeString(out, c.Sprintf("ok\t%.3fs\n", time.Since(from).Seconds()))
-		return nil
+			from := time.Now()
+			if err := next(in); err != nil {
+				c := cfg.Theme.Failed.with(color.Bold)
+				writeString(out, c.sprintf(colored, "%v\t%.3fs\n", err, time.Since(from).Seconds()))
+				return err
+			}
+
+			c := cfg.Theme.Passed.with(color.Bold)
+			writeString(out, c.sprintf(colored, "ok\t%.3fs\n", time.Since(from).Seconds()))
+			return nil
+		}
 	}
 }
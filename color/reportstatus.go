@@ -3,7 +3,6 @@ package color
 import (
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/goyek/goyek/v3"
 )
 
@@ -11,45 +10,47 @@ import (
 //
 // The format is based on the reports provided by the Go test runner.
 func ReportStatus(next goyek.Runner) goyek.Runner {
-	return func(in goyek.Input) goyek.Result {
-		out := outputOrDiscard(in.Output)
-		in.Output = out
-		c := color.New(color.FgBlue)
-
-		// report start task
-		writeString(out, c.Sprintf("===== TASK  %s\n", in.TaskName))
-		start := time.Now()
-
-		// run
-		res := next(in)
-
-		// report task end
-		c = color.New(color.FgGreen)
-		status := "PASS"
-		switch res.Status {
-		case goyek.StatusFailed:
-			c = color.New(color.FgRed)
-			status = "FAIL"
-		case goyek.StatusSkipped:
-			c = color.New(color.FgYellow)
-			status = "SKIP"
-		case goyek.StatusNotRun:
-			status = "NOOP"
-		}
-		writeString(out, c.Sprintf("----- %s: %s (%.2fs)\n", status, in.TaskName, time.Since(start).Seconds()))
-
-		// report panic if happened
-		if res.PanicStack != nil {
-			var panicHeader string
-			if res.PanicValue != nil {
-				panicHeader = c.Sprintf("panic: %v", res.PanicValue)
-			} else {
-				panicHeader = c.Sprint("panic(nil) or runtime.Goexit() called")
+	return NewReportStatus()(next)
+}
+
+// NewReportStatus returns a middleware like [ReportStatus]
+// configured using the options.
+func NewReportStatus(opts ...StatusOption) goyek.Middleware {
+	cfg := newConfig(opts, StatusOption.applyStatus)
+	return func(next goyek.Runner) goyek.Runner {
+		return func(in goyek.Input) goyek.Result {
+			out := outputOrDiscard(in.Output)
+			in.Output = out
+			colored := Enabled(out)
+			theme := cfg.Theme
+
+			// report start task
+			rec := StatusRecord{TaskName: in.TaskName, Parallel: in.Parallel}
+			writeString(out, theme.Task.sprint(colored, cfg.StatusFormat.startRecord(rec)))
+			start := time.Now()
+
+			// run
+			res := next(in)
+
+			// report task end
+			status, c := theme.status(res.Status)
+			rec.Status = status
+			rec.Duration = time.Since(start)
+			writeString(out, c.sprint(colored, cfg.StatusFormat.endRecord(rec)))
+
+			// report panic if happened
+			if res.PanicStack != nil {
+				var panicHeader string
+				if res.PanicValue != nil {
+					panicHeader = c.sprintf(colored, "panic: %v", res.PanicValue)
+				} else {
+					panicHeader = c.sprint(colored, "panic(nil) or runtime.Goexit() called")
+				}
+				panicStack := formatStack(string(res.PanicStack), cfg, c, colored)
+				writeString(out, panicHeader+"\n\n"+panicStack)
 			}
-			panicStack := c.Sprintf("%s", res.PanicStack)
-			writeString(out, panicHeader+"\n\n"+panicStack)
-		}
 
-		return res
+			return res
+		}
 	}
 }
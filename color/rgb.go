@@ -0,0 +1,201 @@
+package color
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RGB is a 24-bit color.
+type RGB struct {
+	R, G, B uint8
+}
+
+// Hex parses a color in the "#rrggbb" or "#rgb" form.
+// The leading "#" is optional.
+func Hex(s string) (RGB, error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) == len("rgb") {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != len("rrggbb") {
+		return RGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	return RGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil //nolint:gosec // truncation is intended
+}
+
+// MustHex is like [Hex] but panics if the color cannot be parsed.
+func MustHex(s string) RGB {
+	c, err := Hex(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// ColorDepth is the number of colors supported by the terminal.
+type ColorDepth int
+
+const (
+	// Depth16 is the basic 16 color palette.
+	Depth16 ColorDepth = iota
+	// Depth256 is the xterm 256 color palette.
+	Depth256
+	// DepthTrueColor is the 24-bit color.
+	DepthTrueColor
+)
+
+// DetectColorDepth returns the number of colors supported by the terminal.
+//
+// The decision is made in the following order:
+//   - FORCE_COLOR is "3": true color, "2": 256 colors, "1": 16 colors,
+//   - COLORTERM is "truecolor" or "24bit": true color,
+//   - TERM ends with "-direct" or contains "truecolor" or "24bit": true color,
+//   - TERM contains "256color": 256 colors,
+//   - otherwise: 16 colors.
+func DetectColorDepth() ColorDepth {
+	switch os.Getenv("FORCE_COLOR") {
+	case "3":
+		return DepthTrueColor
+	case "2":
+		return Depth256
+	case "1":
+		return Depth16
+	}
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return DepthTrueColor
+	}
+	term := os.Getenv("TERM")
+	switch {
+	case strings.HasSuffix(term, "-direct"), strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"):
+		return DepthTrueColor
+	case strings.Contains(term, "256color"):
+		return Depth256
+	default:
+		return Depth16
+	}
+}
+
+// SGR parameters.
+const (
+	sgrReset       = 0
+	sgrBold        = 1
+	sgrFaint       = 2
+	sgrItalic      = 3
+	sgrUnderline   = 4
+	sgrNormal      = 22
+	sgrNoItalic    = 23
+	sgrNoUnderline = 24
+	sgrFgDefault   = 39
+	sgrBgDefault   = 49
+
+	sgrFg      = 30
+	sgrFgHi    = 90
+	sgrBgShift = 10
+	sgrFgExt   = 38
+	sgrExt256  = 5
+	sgrExtRGB  = 2
+)
+
+// params returns the SGR parameters of the color downgraded to the depth.
+func (c RGB) params(depth ColorDepth, background bool) []string {
+	shift := 0
+	if background {
+		shift = sgrBgShift
+	}
+	switch depth {
+	case DepthTrueColor:
+		return []string{
+			strconv.Itoa(sgrFgExt + shift), strconv.Itoa(sgrExtRGB),
+			strconv.Itoa(int(c.R)), strconv.Itoa(int(c.G)), strconv.Itoa(int(c.B)),
+		}
+	case Depth256:
+		return []string{strconv.Itoa(sgrFgExt + shift), strconv.Itoa(sgrExt256), strconv.Itoa(c.ansi256())}
+	default:
+		i := c.ansi16()
+		if i < basicColors {
+			return []string{strconv.Itoa(sgrFg + shift + i)}
+		}
+		return []string{strconv.Itoa(sgrFgHi + shift + i - basicColors)}
+	}
+}
+
+// ansi256 returns the nearest color of the xterm 256 color palette
+// from the 6x6x6 color cube or the grayscale ramp.
+func (c RGB) ansi256() int {
+	const (
+		cubeStart = 16
+		grayStart = 232
+		grayLen   = 24
+	)
+	ri, gi, bi := cubeIndex(c.R), cubeIndex(c.G), cubeIndex(c.B)
+	cube := RGB{cubeLevels[ri], cubeLevels[gi], cubeLevels[bi]}
+
+	// The gray levels are 8, 18, ..., 238.
+	const grayFirst, grayStep = 8, 10
+	avg := (int(c.R) + int(c.G) + int(c.B)) / 3 //nolint:mnd // average of 3 components
+	level := min(max((avg-grayFirst+grayStep/2)/grayStep, 0), grayLen-1)
+	v := uint8(grayFirst + grayStep*level) //nolint:gosec // at most 238
+	gray := RGB{v, v, v}
+
+	if c.distance(gray) < c.distance(cube) {
+		return grayStart + level
+	}
+	return cubeStart + 36*ri + 6*gi + bi //nolint:mnd // 6x6x6 color cube
+}
+
+// cubeLevels are the component values of the xterm 256 color cube.
+var cubeLevels = [...]uint8{0, 95, 135, 175, 215, 255}
+
+// cubeIndex returns the index of the nearest color cube level.
+func cubeIndex(v uint8) int {
+	best := 0
+	for i, l := range cubeLevels {
+		if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// basicColors is the number of normal colors in the basic palette.
+const basicColors = 8
+
+// ansi16Palette is the xterm default basic palette. The first 8 colors
+// are the normal colors and the next 8 are the high intensity ones.
+var ansi16Palette = [...]RGB{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// ansi16 returns the index of the nearest color of the basic palette.
+func (c RGB) ansi16() int {
+	best := 0
+	for i, p := range ansi16Palette {
+		if c.distance(p) < c.distance(ansi16Palette[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// distance returns the squared Euclidean distance between the colors.
+func (c RGB) distance(o RGB) int {
+	dr, dg, db := absDiff(c.R, o.R), absDiff(c.G, o.G), absDiff(c.B, o.B)
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
@@ -0,0 +1,92 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestHex(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    goyekcolor.RGB
+		wantErr bool
+	}{
+		{in: "#ff8000", want: goyekcolor.RGB{R: 255, G: 128, B: 0}},
+		{in: "0a0B0c", want: goyekcolor.RGB{R: 10, G: 11, B: 12}},
+		{in: "#f80", want: goyekcolor.RGB{R: 255, G: 136, B: 0}},
+		{in: "#ff80", wantErr: true},
+		{in: "#gg8000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := goyekcolor.Hex(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		name       string
+		forceColor string
+		colorTerm  string
+		term       string
+		want       goyekcolor.ColorDepth
+	}{
+		{name: "default", term: "xterm", want: goyekcolor.Depth16},
+		{name: "colorterm", colorTerm: "truecolor", term: "xterm", want: goyekcolor.DepthTrueColor},
+		{name: "term 256", term: "xterm-256color", want: goyekcolor.Depth256},
+		{name: "term direct", term: "xterm-direct", want: goyekcolor.DepthTrueColor},
+		{name: "force 2", forceColor: "2", colorTerm: "truecolor", want: goyekcolor.Depth256},
+		{name: "force 1", forceColor: "1", term: "xterm-256color", want: goyekcolor.Depth16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+			t.Setenv("COLORTERM", tt.colorTerm)
+			t.Setenv("TERM", tt.term)
+
+			if got := goyekcolor.DetectColorDepth(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyleRGB(t *testing.T) {
+	style := goyekcolor.NewStyle().
+		Foreground(goyekcolor.MustHex("#ff8700")).
+		Background(goyekcolor.MustHex("#303030"))
+	tests := []struct {
+		name      string
+		colorTerm string
+		term      string
+		want      string
+	}{
+		{name: "true color", colorTerm: "truecolor", want: "\x1b[38;2;255;135;0;48;2;48;48;48mtext\x1b[0m"},
+		{name: "256 colors", term: "xterm-256color", want: "\x1b[38;5;208;48;5;236mtext\x1b[0m"},
+		{name: "16 colors", term: "xterm", want: "\x1b[33;40mtext\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forceColor(t)
+			t.Setenv("FORCE_COLOR", "true")
+			t.Setenv("COLORTERM", tt.colorTerm)
+			t.Setenv("TERM", tt.term)
+			sb := &strings.Builder{}
+
+			goyekcolor.FormatStack(sb, []byte("text"), goyekcolor.WithTheme(goyekcolor.Theme{Failed: style}))
+
+			if got := sb.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package color
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// writeSource writes the lines of the file around the line.
+// The line itself is marked and highlighted if colored is true.
+// Nothing is written if the file cannot be read.
+func writeSource(buf *strings.Builder, file string, line, context int, colored bool) {
+	src, err := os.ReadFile(file) //nolint:gosec // reading the source of the call site is intended
+	if err != nil {
+		return
+	}
+	lines := strings.Split(string(src), "\n")
+	if line < 1 || line > len(lines) {
+		return
+	}
+	from := max(line-context, 1)
+	to := min(line+context, len(lines))
+	width := len(strconv.Itoa(to))
+	highlight := NewStyle(color.Bold)
+	for n := from; n <= to; n++ {
+		code := strings.ReplaceAll(strings.TrimRight(lines[n-1], " \t\r"), "\t", "    ")
+		if n != line {
+			// Source lines are indented like the subsequent lines of the record.
+			fmt.Fprintf(buf, "\n          %*d | %s", width, n, code)
+			continue
+		}
+		buf.WriteString("\n")
+		buf.WriteString(highlight.sprintf(colored, "        > %*d | %s", width, n, code))
+	}
+}
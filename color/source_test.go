@@ -0,0 +1,61 @@
+package color_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestCodeLineLoggerSourceContext(t *testing.T) {
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(goyekcolor.NewCodeLineLogger(goyekcolor.WithSourceContext(1)))
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Log("no source")
+			a.Error("failure")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	want := "      source_test.go:21: no source\n" +
+		"      source_test.go:22: failure\n" +
+		"          21 |             a.Log(\"no source\")\n" +
+		"        > 22 |             a.Error(\"failure\")\n" +
+		"          23 |         },\n"
+	if got := out.String(); got != want {
+		t.Errorf("got output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCodeLineLoggerSourceContextColors(t *testing.T) {
+	forceColor(t)
+
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(goyekcolor.NewCodeLineLogger(goyekcolor.WithSourceContext(2)))
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Fatal("failure")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	got := out.String()
+	if want := "\n\x1b[1m        > 48 |"; !strings.Contains(got, want) {
+		t.Errorf("output %q does not contain %q", got, want)
+	}
+	if want := "          50 |"; !strings.Contains(got, want) {
+		t.Errorf("output %q does not contain %q", got, want)
+	}
+}
@@ -0,0 +1,217 @@
+package color
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// FormatStack writes the goroutine dump, like the panic stack of a task,
+// in a form which is fast to diagnose. The frames of the main module are
+// highlighted, the frames of the Go runtime and goyek are dimmed,
+// the file paths are shortened, and the program counter offsets are removed.
+// Repeated frames are collapsed if configured using [WithCollapsedStack].
+// The output is colorized if [Enabled] reports true for w.
+//
+// A stack which is not a goroutine dump is written as is.
+func FormatStack(w io.Writer, stack []byte, opts ...StackOption) {
+	cfg := newConfig(opts, StackOption.applyStack)
+	writeString(w, formatStack(string(stack), cfg, cfg.Theme.Failed, Enabled(w)))
+}
+
+// stackFrame is a function call in a goroutine dump.
+type stackFrame struct {
+	call string // function with arguments
+	file string // file:line without the program counter offset
+}
+
+// formatStack formats the goroutine dump. Lines which are not
+// recognized use the given style.
+func formatStack(stack string, cfg *config, style Style, colored bool) string {
+	if !strings.HasPrefix(stack, "goroutine ") {
+		return style.sprint(colored, stack)
+	}
+	f := &stackFormatter{
+		collapse:  cfg.CollapseStack,
+		style:     style,
+		dim:       NewStyle(color.FgHiBlack),
+		highlight: style.with(color.Bold),
+		colored:   colored,
+	}
+	lines := strings.Split(strings.TrimSuffix(stack, "\n"), "\n")
+	f.goroot = stackGoroot(lines)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if i+1 < len(lines) && line != "" && !strings.HasPrefix(line, "\t") && strings.HasPrefix(lines[i+1], "\t") {
+			file := strings.TrimPrefix(lines[i+1], "\t")
+			if j := strings.LastIndex(file, " +0x"); j >= 0 {
+				file = file[:j]
+			}
+			f.frames = append(f.frames, stackFrame{call: line, file: file})
+			i++
+			continue
+		}
+		f.flush()
+		if line != "" {
+			f.sb.WriteString(style.sprint(colored, line))
+		}
+		f.sb.WriteByte('\n')
+	}
+	f.flush()
+	return f.sb.String()
+}
+
+// stackFormatter writes the frames of a goroutine dump.
+type stackFormatter struct {
+	collapse  bool
+	style     Style
+	dim       Style
+	highlight Style
+	colored   bool
+	goroot    string
+
+	sb     strings.Builder
+	frames []stackFrame // frames of the current goroutine not written yet
+}
+
+// flush writes the pending frames.
+func (f *stackFormatter) flush() {
+	for i := 0; i < len(f.frames); {
+		frame := f.frames[i]
+		n := 1
+		for f.collapse && i+n < len(f.frames) && sameFrame(f.frames[i+n], frame) {
+			n++
+		}
+		s := f.dim
+		switch {
+		case isUserFrame(frame.call):
+			s = f.highlight
+		case !isInternalCall(frame.call):
+			s = f.style
+		}
+		f.sb.WriteString(s.sprint(f.colored, frame.call))
+		f.sb.WriteByte('\n')
+		f.sb.WriteString(s.sprint(f.colored, "\t"+shortenPath(frame.file, f.goroot)))
+		f.sb.WriteByte('\n')
+		if n > 1 {
+			f.sb.WriteString(f.dim.sprint(f.colored, fmt.Sprintf("\t... repeated %d more times", n-1)))
+			f.sb.WriteByte('\n')
+		}
+		i += n
+	}
+	f.frames = nil
+}
+
+// sameFrame reports whether the frames are calls of the same function
+// at the same place. The arguments are not compared as they usually
+// differ between the recursive calls.
+func sameFrame(a, b stackFrame) bool {
+	return a.file == b.file && funcName(a.call) == funcName(b.call)
+}
+
+// funcName returns the function of the call without the arguments.
+func funcName(call string) string {
+	if strings.HasSuffix(call, ")") {
+		if i := strings.LastIndexByte(call, '('); i > 0 {
+			return call[:i]
+		}
+	}
+	return call
+}
+
+// isInternalCall reports whether the function belongs to the Go runtime or goyek.
+func isInternalCall(call string) bool {
+	return strings.HasPrefix(call, "runtime.") || strings.HasPrefix(call, "runtime/") ||
+		strings.HasPrefix(call, "panic(") || strings.HasPrefix(call, "created by runtime") ||
+		strings.HasPrefix(call, "github.com/goyek/goyek/") || strings.HasPrefix(call, "created by github.com/goyek/goyek/")
+}
+
+// isUserFrame reports whether the function belongs to the main module.
+func isUserFrame(call string) bool {
+	call = strings.TrimPrefix(call, "created by ")
+	if strings.HasPrefix(call, "main.") {
+		return true
+	}
+	mod := mainModule()
+	return mod != "" && (strings.HasPrefix(call, mod+".") || strings.HasPrefix(call, mod+"/"))
+}
+
+var mainModule = sync.OnceValue(func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path
+	}
+	return ""
+})
+
+// stackGoroot returns GOROOT of the program which produced the goroutine dump.
+// It is the directory containing the sources of the runtime functions,
+// as GOROOT is usually not set in the environment.
+func stackGoroot(lines []string) string {
+	const runtimeDir = "/src/runtime/"
+	for i := 0; i+1 < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "runtime.") && !strings.HasPrefix(lines[i], "runtime/") {
+			continue
+		}
+		if j := strings.LastIndex(lines[i+1], runtimeDir); j > 0 && strings.HasPrefix(lines[i+1], "\t") {
+			return lines[i+1][1:j]
+		}
+	}
+	return os.Getenv("GOROOT")
+}
+
+// shortenPath returns the path relative to the working directory,
+// the given GOROOT, or the module cache.
+func shortenPath(file, goroot string) string {
+	path, line := file, ""
+	if i := strings.LastIndexByte(file, ':'); i > 0 {
+		path, line = file[:i], file[i:]
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, ok := relPath(wd, path); ok {
+			return rel + line
+		}
+	}
+	if goroot != "" {
+		if rel, ok := relPath(filepath.Join(goroot, "src"), path); ok {
+			return "$GOROOT/src/" + rel + line
+		}
+	}
+	if rel, ok := relPath(goModCache(), path); ok {
+		return "$GOMODCACHE/" + rel + line
+	}
+	return file
+}
+
+// relPath returns the path relative to the directory if it is inside it.
+func relPath(dir, path string) (string, bool) {
+	if dir == "" || !filepath.IsAbs(path) {
+		return "", false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// goModCache returns the module cache directory without running go env.
+func goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
@@ -0,0 +1,106 @@
+package color_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestFormatStack(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(wd, "build", "main.go")
+	stack := "goroutine 7 [running]:\n" +
+		"runtime/debug.Stack()\n" +
+		"\t/opt/go/src/runtime/debug/stack.go:26 +0x5e\n" +
+		"main.rec(0x2)\n" +
+		"\t" + file + ":13 +0x73\n" +
+		"main.rec(0x1)\n" +
+		"\t" + file + ":13 +0x73\n" +
+		"main.rec(0x0)\n" +
+		"\t" + file + ":13 +0x73\n" +
+		"example.com/lib.Do()\n" +
+		"\t/src/lib/lib.go:5 +0x10\n" +
+		"github.com/goyek/goyek/v3.(*A).run.func1()\n" +
+		"\t/src/goyek/runner.go:20 +0x25\n" +
+		"created by github.com/goyek/goyek/v3.(*A).run in goroutine 1\n" +
+		"\t/src/goyek/runner.go:18 +0x67\n"
+
+	t.Run("plain", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		t.Setenv("GOROOT", "") // derived from the runtime frames
+		sb := &strings.Builder{}
+
+		goyekcolor.FormatStack(sb, []byte(stack))
+
+		want := "goroutine 7 [running]:\n" +
+			"runtime/debug.Stack()\n" +
+			"\t$GOROOT/src/runtime/debug/stack.go:26\n" +
+			"main.rec(0x2)\n" +
+			"\tbuild/main.go:13\n" +
+			"main.rec(0x1)\n" +
+			"\tbuild/main.go:13\n" +
+			"main.rec(0x0)\n" +
+			"\tbuild/main.go:13\n" +
+			"example.com/lib.Do()\n" +
+			"\t/src/lib/lib.go:5\n" +
+			"github.com/goyek/goyek/v3.(*A).run.func1()\n" +
+			"\t/src/goyek/runner.go:20\n" +
+			"created by github.com/goyek/goyek/v3.(*A).run in goroutine 1\n" +
+			"\t/src/goyek/runner.go:18\n"
+		if got := sb.String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("collapsed", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		sb := &strings.Builder{}
+
+		goyekcolor.FormatStack(sb, []byte(stack), goyekcolor.WithCollapsedStack())
+
+		want := "main.rec(0x2)\n" +
+			"\tbuild/main.go:13\n" +
+			"\t... repeated 2 more times\n" +
+			"example.com/lib.Do()\n"
+		if got := sb.String(); !strings.Contains(got, want) {
+			t.Errorf("got:\n%s\nshould contain:\n%s", got, want)
+		}
+	})
+
+	t.Run("colored", func(t *testing.T) {
+		forceColor(t)
+		sb := &strings.Builder{}
+
+		goyekcolor.FormatStack(sb, []byte(stack))
+
+		got := sb.String()
+		for _, want := range []string{
+			ansiRed + "goroutine 7 [running]:" + ansiReset + "\n",
+			"\x1b[90mruntime/debug.Stack()" + ansiReset + "\n",
+			"\x1b[1;31mmain.rec(0x2)\x1b[22;0m" + "\n",
+			ansiRed + "example.com/lib.Do()" + ansiReset + "\n",
+			"\x1b[90mgithub.com/goyek/goyek/v3.(*A).run.func1()" + ansiReset + "\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("got:\n%q\nshould contain:\n%q", got, want)
+			}
+		}
+	})
+}
+
+func TestFormatStackUnknown(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	sb := &strings.Builder{}
+
+	goyekcolor.FormatStack(sb, []byte("stack\n"))
+
+	if got, want := sb.String(), "stack\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// StatusRecord is the data passed to the templates of [StatusFormat].
+type StatusRecord struct {
+	TaskName string
+	Status   string        // PASS, FAIL, SKIP, or NOOP; empty in the start record
+	Duration time.Duration // zero in the start record
+	Parallel bool          // whether the task is run in parallel
+}
+
+// StatusFormat defines the records written by [NewReportStatus]
+// when a task starts and ends.
+type StatusFormat struct {
+	start, end *template.Template
+}
+
+// Default status record templates. They reproduce the reports
+// provided by the Go test runner.
+const (
+	DefaultStatusStart = "===== TASK  {{.TaskName}}\n"
+	DefaultStatusEnd   = "----- {{.Status}}: {{.TaskName}} ({{printf \"%.2fs\" .Duration.Seconds}})\n"
+)
+
+var defaultStatusFormat = MustParseStatusFormat(DefaultStatusStart, DefaultStatusEnd)
+
+// ParseStatusFormat parses the templates of the records written
+// when a task starts and ends. The templates use the [text/template]
+// syntax with [StatusRecord] as the data.
+// The records should end with a newline.
+//
+// The templates are executed once with sample data
+// so that the errors like unknown fields are reported.
+func ParseStatusFormat(start, end string) (*StatusFormat, error) {
+	startTmpl, err := template.New("start").Parse(start)
+	if err != nil {
+		return nil, fmt.Errorf("parse status format: %w", err)
+	}
+	endTmpl, err := template.New("end").Parse(end)
+	if err != nil {
+		return nil, fmt.Errorf("parse status format: %w", err)
+	}
+	f := &StatusFormat{start: startTmpl, end: endTmpl}
+	sample := StatusRecord{TaskName: "task", Status: "PASS", Duration: time.Second}
+	if _, err := f.execute(f.start, sample); err != nil {
+		return nil, fmt.Errorf("parse status format: %w", err)
+	}
+	if _, err := f.execute(f.end, sample); err != nil {
+		return nil, fmt.Errorf("parse status format: %w", err)
+	}
+	return f, nil
+}
+
+// MustParseStatusFormat is like [ParseStatusFormat] but panics
+// if the templates cannot be parsed.
+func MustParseStatusFormat(start, end string) *StatusFormat {
+	f, err := ParseStatusFormat(start, end)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func (f *StatusFormat) execute(tmpl *template.Template, rec StatusRecord) (string, error) {
+	sb := &strings.Builder{}
+	err := tmpl.Execute(sb, rec)
+	return sb.String(), err
+}
+
+// startRecord returns the record written when the task starts.
+// The default format is used if the template fails.
+func (f *StatusFormat) startRecord(rec StatusRecord) string {
+	s, err := f.execute(f.start, rec)
+	if err != nil {
+		s, _ = f.execute(defaultStatusFormat.start, rec)
+	}
+	return s
+}
+
+// endRecord returns the record written when the task ends.
+// The default format is used if the template fails.
+func (f *StatusFormat) endRecord(rec StatusRecord) string {
+	s, err := f.execute(f.end, rec)
+	if err != nil {
+		s, _ = f.execute(defaultStatusFormat.end, rec)
+	}
+	return s
+}
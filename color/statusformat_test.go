@@ -0,0 +1,66 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestStatusFormat(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	format, err := goyekcolor.ParseStatusFormat(
+		"start {{.TaskName}}{{if .Parallel}} (parallel){{end}}\n",
+		"end {{.TaskName}} {{.Status}} {{printf \"%.0fh\" .Duration.Hours}}\n",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &strings.Builder{}
+	runner := goyekcolor.NewReportStatus(goyekcolor.WithStatusFormat(format))(func(goyek.Input) goyek.Result {
+		return goyek.Result{Status: goyek.StatusFailed}
+	})
+
+	runner(goyek.Input{Output: out, TaskName: "task", Parallel: true})
+
+	want := "start task (parallel)\nend task FAIL 0h\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStatusFormatDefault(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	out := &strings.Builder{}
+	format := goyekcolor.MustParseStatusFormat(goyekcolor.DefaultStatusStart, goyekcolor.DefaultStatusEnd)
+	runner := goyekcolor.NewReportStatus(goyekcolor.WithStatusFormat(format))(func(goyek.Input) goyek.Result {
+		return goyek.Result{Status: goyek.StatusPassed}
+	})
+
+	runner(goyek.Input{Output: out, TaskName: "task"})
+
+	want := "===== TASK  task\n----- PASS: task (0.00s)\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseStatusFormatError(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		end   string
+	}{
+		{name: "syntax", start: "{{.TaskName", end: goyekcolor.DefaultStatusEnd},
+		{name: "unknown field", start: goyekcolor.DefaultStatusStart, end: "{{.Name}}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := goyekcolor.ParseStatusFormat(tt.start, tt.end); err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
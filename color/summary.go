@@ -0,0 +1,92 @@
+package color
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
+)
+
+// Summary prints a summary of the flow execution.
+// It lists the tasks sorted by duration, slowest first,
+// followed by the failed and skipped tasks with the first line
+// of their first error or skip message.
+//
+// Both [Summary.Middleware] and [Summary.ExecutorMiddleware]
+// have to be used. The runner middleware records the tasks and
+// the executor middleware prints the summary after the flow execution.
+// The runner middleware wraps the logger to capture the messages.
+type Summary struct {
+	cfg *config
+	rec recorder
+}
+
+// NewSummary returns a Summary configured using the options.
+func NewSummary(opts ...Option) *Summary {
+	return &Summary{cfg: newConfig(opts, Option.apply)}
+}
+
+// Middleware is a runner middleware, which records the outcome of each task.
+func (s *Summary) Middleware(next goyek.Runner) goyek.Runner {
+	return s.rec.middleware(next)
+}
+
+// ExecutorMiddleware is an executor middleware, which prints the summary
+// after the flow execution.
+func (s *Summary) ExecutorMiddleware(next goyek.Executor) goyek.Executor {
+	return func(in goyek.ExecuteInput) error {
+		out := outputOrDiscard(in.Output)
+		in.Output = out
+
+		s.rec.reset()
+		err := next(in)
+		writeString(out, s.format(Enabled(out)))
+		return err
+	}
+}
+
+func (s *Summary) format(colored bool) string {
+	records := s.rec.taskRecords()
+	if len(records) == 0 {
+		return ""
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Duration > records[j].Duration
+	})
+	nameWidth := 0
+	for _, rec := range records {
+		nameWidth = max(nameWidth, len(rec.Name))
+	}
+
+	theme := s.cfg.Theme
+	sb := &strings.Builder{}
+	sb.WriteString(theme.Task.sprint(colored, "===== SUMMARY\n"))
+	for _, rec := range records {
+		status, c := theme.status(rec.Status)
+		sb.WriteString(c.sprintf(colored, "%s  %-*s  %8.2fs\n", status, nameWidth, rec.Name, rec.Duration.Seconds()))
+	}
+	s.formatList(sb, colored, "Failed", goyek.StatusFailed, records)
+	s.formatList(sb, colored, "Skipped", goyek.StatusSkipped, records)
+	return sb.String()
+}
+
+// formatList writes the list of the tasks with the given status.
+func (s *Summary) formatList(sb *strings.Builder, colored bool, title string, status goyek.Status, records []taskRecord) {
+	_, c := s.cfg.Theme.status(status)
+	header := false
+	for _, rec := range records {
+		if rec.Status != status {
+			continue
+		}
+		if !header {
+			sb.WriteString(c.with(color.Bold).sprintf(colored, "%s:\n", title))
+			header = true
+		}
+		if rec.Message == "" {
+			sb.WriteString(c.sprintf(colored, "  %s\n", rec.Name))
+			continue
+		}
+		sb.WriteString(c.sprintf(colored, "  %s: %s\n", rec.Name, rec.Message))
+	}
+}
@@ -0,0 +1,100 @@
+package color_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestSummary(t *testing.T) {
+	summary := goyekcolor.NewSummary()
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(&goyekcolor.CodeLineLogger{})
+	flow.Use(summary.Middleware)
+	flow.UseExecutor(summary.ExecutorMiddleware)
+	flow.Define(goyek.Task{
+		Name: "skip",
+		Action: func(a *goyek.A) {
+			a.Skip("not needed")
+		},
+	})
+	slow := flow.Define(goyek.Task{
+		Name: "slow",
+		Action: func(*goyek.A) {
+			time.Sleep(20 * time.Millisecond)
+		},
+	})
+	flow.Define(goyek.Task{
+		Name: "broken",
+		Deps: goyek.Deps{slow},
+		Action: func(a *goyek.A) {
+			a.Log("some output")
+			helperFn(a)
+			a.Errorf("first error\nwith details")
+			a.Error("second error")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"skip", "broken"})
+
+	got := out.String()
+	want := regexp.MustCompile(`(?s)` +
+		`summary_test.go:41: first error\n.*` +
+		`===== SUMMARY\n` +
+		`PASS  slow    \s+\d+\.\d\ds\n` +
+		`(SKIP  skip    \s+\d+\.\d\ds\nFAIL  broken  \s+\d+\.\d\ds\n|FAIL  broken  \s+\d+\.\d\ds\nSKIP  skip    \s+\d+\.\d\ds\n)` +
+		`Failed:\n` +
+		`  broken: first error\n` +
+		`Skipped:\n` +
+		`  skip: not needed\n$`)
+	if !want.MatchString(got) {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+}
+
+func TestSummaryColors(t *testing.T) {
+	forceColor(t)
+
+	summary := goyekcolor.NewSummary()
+	executor := summary.ExecutorMiddleware(func(in goyek.ExecuteInput) error {
+		runner := summary.Middleware(func(goyek.Input) goyek.Result {
+			return goyek.Result{Status: goyek.StatusFailed}
+		})
+		runner(goyek.Input{Context: in.Context, TaskName: "task", Output: in.Output})
+		return nil
+	})
+	out := &strings.Builder{}
+
+	_ = executor(goyek.ExecuteInput{Context: context.Background(), Output: out})
+
+	for _, want := range []string{
+		ansiBlue + "===== SUMMARY\n" + ansiReset,
+		ansiRed + "FAIL  task",
+		"\x1b[1;31mFailed:\n",
+		ansiRed + "  task\n" + ansiReset,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q does not contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestSummaryEmpty(t *testing.T) {
+	summary := goyekcolor.NewSummary()
+	executor := summary.ExecutorMiddleware(func(goyek.ExecuteInput) error { return nil })
+	out := &strings.Builder{}
+
+	_ = executor(goyek.ExecuteInput{Output: out})
+
+	if got := out.String(); got != "" {
+		t.Errorf("got output %q, want empty", got)
+	}
+}
@@ -0,0 +1,59 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// ReportTeamCity is a runner middleware, which reports each task using
+// TeamCity service messages. The output of the task is put in a block
+// and the task is reported as a test so that its status and duration
+// are shown in the TeamCity build results.
+func ReportTeamCity(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		out := outputOrDiscard(in.Output)
+		in.Output = out
+		name := escapeTeamCity(in.TaskName)
+
+		writeString(out, fmt.Sprintf("##teamcity[blockOpened name='%s' flowId='%s']\n", name, name))
+		writeString(out, fmt.Sprintf("##teamcity[testStarted name='%s' flowId='%s']\n", name, name))
+		start := time.Now()
+
+		res := next(in)
+
+		switch {
+		case res.PanicStack != nil:
+			msg := "panic(nil) or runtime.Goexit() called"
+			if res.PanicValue != nil {
+				msg = fmt.Sprintf("panic: %v", res.PanicValue)
+			}
+			writeString(out, fmt.Sprintf("##teamcity[testFailed name='%s' message='%s' details='%s' flowId='%s']\n",
+				name, escapeTeamCity(msg), escapeTeamCity(string(res.PanicStack)), name))
+		case res.Status == goyek.StatusFailed:
+			writeString(out, fmt.Sprintf("##teamcity[testFailed name='%s' message='task failed' flowId='%s']\n", name, name))
+		case res.Status == goyek.StatusSkipped:
+			writeString(out, fmt.Sprintf("##teamcity[testIgnored name='%s' message='task skipped' flowId='%s']\n", name, name))
+		case res.Status == goyek.StatusNotRun:
+			writeString(out, fmt.Sprintf("##teamcity[testIgnored name='%s' message='task not run' flowId='%s']\n", name, name))
+		}
+		writeString(out, fmt.Sprintf("##teamcity[testFinished name='%s' duration='%d' flowId='%s']\n",
+			name, time.Since(start).Milliseconds(), name))
+		writeString(out, fmt.Sprintf("##teamcity[blockClosed name='%s' flowId='%s']\n", name, name))
+		return res
+	}
+}
+
+// escapeTeamCity escapes the value of a TeamCity service message attribute.
+func escapeTeamCity(s string) string {
+	return strings.NewReplacer(
+		"|", "||",
+		"'", "|'",
+		"\n", "|n",
+		"\r", "|r",
+		"[", "|[",
+		"]", "|]",
+	).Replace(s)
+}
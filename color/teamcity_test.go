@@ -0,0 +1,60 @@
+package color_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestReportTeamCity(t *testing.T) {
+	tests := []struct {
+		name   string
+		result goyek.Result
+		want   string
+	}{
+		{
+			name:   "passed",
+			result: goyek.Result{Status: goyek.StatusPassed},
+		},
+		{
+			name:   "failed",
+			result: goyek.Result{Status: goyek.StatusFailed},
+			want:   "##teamcity[testFailed name='it|'s |[x|]' message='task failed' flowId='it|'s |[x|]']\n",
+		},
+		{
+			name:   "skipped",
+			result: goyek.Result{Status: goyek.StatusSkipped},
+			want:   "##teamcity[testIgnored name='it|'s |[x|]' message='task skipped' flowId='it|'s |[x|]']\n",
+		},
+		{
+			name:   "panic",
+			result: goyek.Result{Status: goyek.StatusFailed, PanicValue: "boom", PanicStack: []byte("stack|\n")},
+			want:   "##teamcity[testFailed name='it|'s |[x|]' message='panic: boom' details='stack|||n' flowId='it|'s |[x|]']\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &strings.Builder{}
+			runner := goyekcolor.ReportTeamCity(func(goyek.Input) goyek.Result { return tc.result })
+
+			runner(goyek.Input{Output: out, TaskName: "it's [x]"})
+
+			want := regexp.MustCompile(`^` +
+				regexp.QuoteMeta("##teamcity[blockOpened name='it|'s |[x|]' flowId='it|'s |[x|]']\n"+
+					"##teamcity[testStarted name='it|'s |[x|]' flowId='it|'s |[x|]']\n"+
+					tc.want+
+					"##teamcity[testFinished name='it|'s |[x|]' duration='") +
+				`\d+` +
+				regexp.QuoteMeta("' flowId='it|'s |[x|]']\n"+
+					"##teamcity[blockClosed name='it|'s |[x|]' flowId='it|'s |[x|]']\n") +
+				`$`)
+			if got := out.String(); !want.MatchString(got) {
+				t.Errorf("unexpected output: %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package color
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Enabled reports whether the output written to w is colorized.
+//
+// The decision is made in the following order:
+//   - NoColor was called or NO_COLOR is set to a non-empty string: disabled,
+//   - FORCE_COLOR is set to a non-empty string: enabled,
+//     unless it is "0" or "false",
+//   - CLICOLOR_FORCE is set to a non-empty string other than "0": enabled,
+//   - CLICOLOR is "0" or TERM is "dumb": disabled,
+//   - otherwise: enabled if w is a terminal.
+//
+// A writer is considered a terminal if it is a file descriptor
+// referring to a terminal. Writers having an Unwrap() io.Writer method
+// are unwrapped. The output of other writers, like buffers used by
+// middlewares, is assumed to end up in the writer set using
+// [SetFlowOutput]. If none is set, it is not colorized.
+func Enabled(w io.Writer) bool {
+	if noColor.Load() || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch force := os.Getenv("FORCE_COLOR"); force {
+	case "":
+	case "0", "false":
+		return false
+	default:
+		return true
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if fd, ok := fileDescriptor(w); ok {
+		return isTerminal(fd)
+	}
+	if out := flowOutput.Load(); out != nil {
+		if fd, ok := fileDescriptor(out.w); ok {
+			return isTerminal(fd)
+		}
+	}
+	return false
+}
+
+// flowOutput is the writer set using SetFlowOutput.
+var flowOutput atomic.Pointer[struct{ w io.Writer }]
+
+// SetFlowOutput sets the output of the flow, which [Enabled] uses
+// for the writers that cannot be unwrapped, like the buffers used
+// by middlewares and the output synchronized by goyek.
+// Use it with the output of the flow writing to a terminal,
+// for example:
+//
+//	color.SetFlowOutput(goyek.Output())
+//
+// A nil writer unsets it.
+func SetFlowOutput(w io.Writer) {
+	if w == nil {
+		flowOutput.Store(nil)
+		return
+	}
+	flowOutput.Store(&struct{ w io.Writer }{w})
+}
+
+// fileDescriptor returns the file descriptor backing the writer.
+func fileDescriptor(w io.Writer) (uintptr, bool) {
+	for w != nil {
+		switch v := w.(type) {
+		case interface{ Fd() uintptr }:
+			return v.Fd(), true
+		case interface{ Unwrap() io.Writer }:
+			w = v.Unwrap()
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+func isTerminal(fd uintptr) bool {
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
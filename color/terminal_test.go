@@ -0,0 +1,81 @@
+package color_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "not terminal", want: false},
+		{name: "FORCE_COLOR", env: map[string]string{"FORCE_COLOR": "1"}, want: true},
+		{name: "FORCE_COLOR=0", env: map[string]string{"FORCE_COLOR": "0", "CLICOLOR_FORCE": "1"}, want: false},
+		{name: "FORCE_COLOR=false", env: map[string]string{"FORCE_COLOR": "false"}, want: false},
+		{name: "FORCE_COLOR with TERM=dumb", env: map[string]string{"FORCE_COLOR": "true", "TERM": "dumb"}, want: true},
+		{name: "NO_COLOR", env: map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, want: false},
+		{name: "CLICOLOR_FORCE", env: map[string]string{"CLICOLOR_FORCE": "1", "CLICOLOR": "0"}, want: true},
+		{name: "CLICOLOR_FORCE=0", env: map[string]string{"CLICOLOR_FORCE": "0"}, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE", "CLICOLOR", "TERM"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			if got := goyekcolor.Enabled(&strings.Builder{}); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnabledFile(t *testing.T) {
+	for _, key := range []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE", "CLICOLOR", "TERM"} {
+		t.Setenv(key, "")
+	}
+	f, err := os.Create(t.TempDir() + "/out.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if goyekcolor.Enabled(f) {
+		t.Error("colors enabled for a regular file")
+	}
+	if goyekcolor.Enabled(unwrapWriter{f}) {
+		t.Error("colors enabled for a wrapped regular file")
+	}
+}
+
+func TestEnabledFlowOutput(t *testing.T) {
+	for _, key := range []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE", "CLICOLOR", "TERM"} {
+		t.Setenv(key, "")
+	}
+	f, err := os.Create(t.TempDir() + "/out.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	goyekcolor.SetFlowOutput(f)
+	t.Cleanup(func() { goyekcolor.SetFlowOutput(nil) })
+
+	if goyekcolor.Enabled(&strings.Builder{}) {
+		t.Error("colors enabled for a buffer written to a regular file")
+	}
+}
+
+type unwrapWriter struct {
+	io.Writer
+}
+
+func (w unwrapWriter) Unwrap() io.Writer {
+	return w.Writer
+}
@@ -0,0 +1,222 @@
+package color
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+)
+
+// TestJSON writes the flow execution as a stream of events in the format
+// of go test -json (see go doc test2json), so that it can be consumed
+// by tools like gotestsum. Each task is reported as a test
+// and the flow is reported as a package.
+//
+// The task statuses are mapped to the pass, fail, and skip actions.
+// Tasks which were not run (NOOP) are reported as skipped.
+// The output of each task is reported using the output actions.
+//
+// Both [TestJSON.Middleware] and [TestJSON.ExecutorMiddleware]
+// should be used. Without the executor middleware, only the task events
+// are written. The task events are written to the task output.
+// If [TestJSON.Middleware] is used before middleware.BufferParallel,
+// the events of parallel tasks are not interleaved.
+// It has to be used after middleware.SilentNonFailed, which would
+// otherwise drop the events of the tasks which did not fail.
+type TestJSON struct {
+	pkg string
+
+	mu     sync.Mutex
+	active bool // set during the flow execution
+}
+
+// testEvent is an event written by TestJSON.
+type testEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test,omitempty"`
+	Elapsed *float64  `json:"Elapsed,omitempty"`
+	Output  string    `json:"Output,omitempty"`
+}
+
+// testEventMarker prefixes the task events written to the output
+// of the flow execution, so that they are not reported as the package output.
+const testEventMarker = "\x00goyek-testjson\x00"
+
+// NewTestJSON returns a TestJSON reporting the flow as the package.
+// If the package name is empty, the program name is used.
+func NewTestJSON(pkg string) *TestJSON {
+	return &TestJSON{pkg: pkg}
+}
+
+// Middleware is a runner middleware, which reports the task as a test.
+func (t *TestJSON) Middleware(next goyek.Runner) goyek.Runner {
+	return func(in goyek.Input) goyek.Result {
+		t.mu.Lock()
+		active := t.active
+		t.mu.Unlock()
+
+		tw := &testEventWriter{w: outputOrDiscard(in.Output), pkg: t.packageName(), test: in.TaskName}
+		if active {
+			tw.marker = testEventMarker
+		}
+		tw.event("run", nil)
+		tw.output(fmt.Sprintf("=== RUN   %s\n", in.TaskName))
+		in.Output = tw
+		start := time.Now()
+
+		res := next(in)
+
+		if res.PanicStack != nil {
+			if res.PanicValue != nil {
+				tw.output(fmt.Sprintf("panic: %v\n\n", res.PanicValue))
+			} else {
+				tw.output("panic(nil) or runtime.Goexit() called\n\n")
+			}
+			tw.output(string(res.PanicStack))
+		}
+		tw.flush()
+		status, action := "PASS", "pass"
+		switch res.Status {
+		case goyek.StatusFailed:
+			status, action = "FAIL", "fail"
+		case goyek.StatusSkipped, goyek.StatusNotRun:
+			status, action = "SKIP", "skip"
+		}
+		elapsed := time.Since(start).Seconds()
+		tw.output(fmt.Sprintf("--- %s: %s (%.2fs)\n", status, in.TaskName, elapsed))
+		tw.event(action, &elapsed)
+		return res
+	}
+}
+
+// ExecutorMiddleware is an executor middleware, which reports the flow
+// as a package. Other output written during the flow execution is reported
+// as the package output.
+func (t *TestJSON) ExecutorMiddleware(next goyek.Executor) goyek.Executor {
+	return func(in goyek.ExecuteInput) error {
+		t.mu.Lock()
+		t.active = true
+		t.mu.Unlock()
+		defer func() {
+			t.mu.Lock()
+			t.active = false
+			t.mu.Unlock()
+		}()
+
+		// The task events written by Middleware are passed through.
+		pw := &testEventWriter{w: outputOrDiscard(in.Output), pkg: t.packageName(), passMarked: true}
+		pw.event("start", nil)
+		in.Output = pw
+		start := time.Now()
+
+		err := next(in)
+
+		pw.flush()
+		status, action := "PASS", "pass"
+		if err != nil {
+			status, action = "FAIL", "fail"
+		}
+		elapsed := time.Since(start).Seconds()
+		pw.output(status + "\n")
+		pw.event(action, &elapsed)
+		return err
+	}
+}
+
+func (t *TestJSON) packageName() string {
+	if t.pkg != "" {
+		return t.pkg
+	}
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+}
+
+// testEventWriter writes each line as an output event.
+type testEventWriter struct {
+	w          io.Writer
+	pkg        string
+	test       string
+	marker     string // prefix of the written events
+	passMarked bool   // whether the events marked by other writers are written as is
+
+	mu      sync.Mutex
+	partial []byte // incomplete line
+}
+
+func (tw *testEventWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.passMarked && bytes.HasPrefix(p, []byte(testEventMarker)) {
+		// The task events are written in whole lines, possibly many at once
+		// by buffering middlewares, even in the middle of an incomplete line.
+		tw.writeLines(p)
+		return len(p), nil
+	}
+	tw.partial = append(tw.partial, p...)
+	if i := bytes.LastIndexByte(tw.partial, '\n'); i >= 0 {
+		tw.writeLines(tw.partial[:i+1])
+		tw.partial = append([]byte(nil), tw.partial[i+1:]...)
+	}
+	return len(p), nil
+}
+
+// writeLines writes each line as an output event. The lines
+// with the task events marked by other writers are written as is
+// if passMarked is set. It must be called with the lock held.
+func (tw *testEventWriter) writeLines(b []byte) {
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			i = len(b) - 1
+		}
+		line := b[:i+1]
+		b = b[i+1:]
+		if tw.passMarked && bytes.HasPrefix(line, []byte(testEventMarker)) {
+			writeString(tw.w, string(line[len(testEventMarker):]))
+			continue
+		}
+		tw.write(testEvent{Action: "output", Output: string(line)})
+	}
+}
+
+// flush writes the incomplete line.
+func (tw *testEventWriter) flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if len(tw.partial) > 0 {
+		tw.write(testEvent{Action: "output", Output: string(tw.partial)})
+		tw.partial = nil
+	}
+}
+
+func (tw *testEventWriter) output(s string) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.write(testEvent{Action: "output", Output: s})
+}
+
+func (tw *testEventWriter) event(action string, elapsed *float64) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.write(testEvent{Action: action, Elapsed: elapsed})
+}
+
+// write writes the event. It must be called with the lock held.
+func (tw *testEventWriter) write(e testEvent) {
+	e.Time = time.Now()
+	e.Package = tw.pkg
+	e.Test = tw.test
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	writeString(tw.w, tw.marker+string(b)+"\n")
+}
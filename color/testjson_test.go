@@ -0,0 +1,207 @@
+package color_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goyek/goyek/v3"
+	"github.com/goyek/goyek/v3/middleware"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+type testEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed *float64
+	Output  string
+}
+
+func TestTestJSON(t *testing.T) {
+	testJSON := goyekcolor.NewTestJSON("build")
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.Use(testJSON.Middleware)
+	flow.UseExecutor(testJSON.ExecutorMiddleware)
+	noop := flow.Define(goyek.Task{Name: "noop"})
+	skip := flow.Define(goyek.Task{
+		Name: "skip",
+		Deps: goyek.Deps{noop},
+		Action: func(a *goyek.A) {
+			a.Skip("skipped")
+		},
+	})
+	flow.Define(goyek.Task{
+		Name: "fail",
+		Deps: goyek.Deps{skip},
+		Action: func(a *goyek.A) {
+			_, _ = io.WriteString(a.Output(), "line\npartial")
+			a.Fail()
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"fail"})
+
+	want := []testEvent{
+		{Action: "start"},
+		{Action: "run", Test: "noop"},
+		{Action: "output", Test: "noop", Output: "=== RUN   noop\n"},
+		{Action: "output", Test: "noop", Output: "--- SKIP: noop (0.00s)\n"},
+		{Action: "skip", Test: "noop"},
+		{Action: "run", Test: "skip"},
+		{Action: "output", Test: "skip", Output: "=== RUN   skip\n"},
+		{Action: "output", Test: "skip", Output: "skipped\n"},
+		{Action: "output", Test: "skip", Output: "--- SKIP: skip (0.00s)\n"},
+		{Action: "skip", Test: "skip"},
+		{Action: "run", Test: "fail"},
+		{Action: "output", Test: "fail", Output: "=== RUN   fail\n"},
+		{Action: "output", Test: "fail", Output: "line\n"},
+		{Action: "output", Test: "fail", Output: "partial"},
+		{Action: "output", Test: "fail", Output: "--- FAIL: fail (0.00s)\n"},
+		{Action: "fail", Test: "fail"},
+		{Action: "output", Output: "FAIL\n"},
+		{Action: "fail"},
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d events, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i, line := range lines {
+		var got testEvent
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		if got.Package != "build" {
+			t.Errorf("got package %q, want %q", got.Package, "build")
+		}
+		if isFinal := got.Action != "output" && got.Action != "run" && got.Action != "start"; isFinal != (got.Elapsed != nil) {
+			t.Errorf("unexpected elapsed time in event %s", line)
+		}
+		got.Package, got.Elapsed = "", nil
+		if got != want[i] {
+			t.Errorf("got event %+v, want %+v", got, want[i])
+		}
+	}
+}
+
+func TestTestJSONPass(t *testing.T) {
+	testJSON := goyekcolor.NewTestJSON("")
+	executor := testJSON.ExecutorMiddleware(func(in goyek.ExecuteInput) error {
+		_, _ = io.WriteString(in.Output, "flow output\n")
+		return nil
+	})
+	out := &strings.Builder{}
+
+	_ = executor(goyek.ExecuteInput{Output: out})
+
+	got := out.String()
+	for _, want := range []string{
+		`"Action":"output","Package":"color.test","Output":"flow output\n"}`,
+		`"Action":"output","Package":"color.test","Output":"PASS\n"}`,
+		`"Action":"pass","Package":"color.test","Elapsed":`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestTestJSONParallel(t *testing.T) {
+	testJSON := goyekcolor.NewTestJSON("build")
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.Use(testJSON.Middleware)
+	flow.UseExecutor(testJSON.ExecutorMiddleware)
+	var deps goyek.Deps
+	for _, name := range []string{"a", "b", "c"} {
+		deps = append(deps, flow.Define(goyek.Task{
+			Name:     name,
+			Parallel: true,
+			Action: func(a *goyek.A) {
+				for i := range 100 {
+					_, _ = fmt.Fprintf(a.Output(), "%s %d\n", name, i)
+				}
+			},
+		}))
+	}
+	flow.Define(goyek.Task{Name: "all", Deps: deps})
+
+	if err := flow.Execute(context.Background(), []string{"all"}); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		var got testEvent
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		if got.Action == "output" && got.Test != "" && strings.HasPrefix(got.Output, got.Test+" ") {
+			outputs[got.Test]++
+		} else if got.Action == "output" && got.Test == "" && got.Output != "PASS\n" {
+			t.Errorf("task output reported as the package output: %q", line)
+		}
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if outputs[name] != 100 {
+			t.Errorf("got %d output events of %s, want 100", outputs[name], name)
+		}
+	}
+}
+
+func TestTestJSONBufferParallel(t *testing.T) {
+	testJSON := goyekcolor.NewTestJSON("build")
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.Use(testJSON.Middleware)
+	flow.Use(middleware.BufferParallel)
+	flow.UseExecutor(testJSON.ExecutorMiddleware)
+	var deps goyek.Deps
+	for _, name := range []string{"a", "b"} {
+		deps = append(deps, flow.Define(goyek.Task{
+			Name:     name,
+			Parallel: true,
+			Action: func(a *goyek.A) {
+				for i := range 10 {
+					_, _ = fmt.Fprintf(a.Output(), "%s %d\n", name, i)
+				}
+			},
+		}))
+	}
+	flow.Define(goyek.Task{
+		Name: "all",
+		Deps: deps,
+		Action: func(a *goyek.A) {
+			_, _ = io.WriteString(a.Output(), "partial")
+		},
+	})
+
+	if err := flow.Execute(context.Background(), []string{"all"}); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		var got testEvent
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		if got.Test == "" && got.Action == "output" && got.Output != "PASS\n" {
+			t.Errorf("task output reported as the package output: %q", line)
+		}
+		actions[got.Test+" "+got.Action]++
+	}
+	for _, name := range []string{"a", "b"} {
+		if actions[name+" run"] != 1 || actions[name+" pass"] != 1 || actions[name+" output"] != 12 {
+			t.Errorf("unexpected events of %s: %v", name, actions)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package color
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
+)
+
+// themeEnv is the environment variable selecting the default theme.
+const themeEnv = "GOYEK_COLOR_THEME"
+
+// Style defines how the text is colorized.
+//
+// The RGB colors set using [Style.Foreground] and [Style.Background]
+// are downgraded to the palette reported by [DetectColorDepth].
+type Style struct {
+	attrs  []color.Attribute
+	fg, bg *RGB
+}
+
+// NewStyle returns a style with the given attributes.
+func NewStyle(attrs ...color.Attribute) Style {
+	return Style{attrs: attrs}
+}
+
+// Foreground returns the style with the given text color.
+func (s Style) Foreground(c RGB) Style {
+	s.fg = &c
+	return s
+}
+
+// Background returns the style with the given background color.
+func (s Style) Background(c RGB) Style {
+	s.bg = &c
+	return s
+}
+
+// with returns the style with additional attributes in front.
+func (s Style) with(attrs ...color.Attribute) Style {
+	s.attrs = append(attrs, s.attrs...)
+	return s
+}
+
+// sprint formats the text like fmt.Sprint and colorizes it if enabled.
+func (s Style) sprint(enabled bool, a ...interface{}) string {
+	if !enabled || s.empty() {
+		return fmt.Sprint(a...)
+	}
+	if s.fg != nil || s.bg != nil {
+		return s.wrap(fmt.Sprint(a...))
+	}
+	return s.color().Sprint(a...)
+}
+
+// sprintf formats the text like fmt.Sprintf and colorizes it if enabled.
+func (s Style) sprintf(enabled bool, format string, a ...interface{}) string {
+	if !enabled || s.empty() {
+		return fmt.Sprintf(format, a...)
+	}
+	if s.fg != nil || s.bg != nil {
+		return s.wrap(fmt.Sprintf(format, a...))
+	}
+	return s.color().Sprintf(format, a...)
+}
+
+func (s Style) empty() bool {
+	return len(s.attrs) == 0 && s.fg == nil && s.bg == nil
+}
+
+// wrap colorizes the text using the RGB colors downgraded
+// to the color depth of the terminal.
+func (s Style) wrap(text string) string {
+	depth := DetectColorDepth()
+	params := make([]string, 0, len(s.attrs))
+	for _, attr := range s.attrs {
+		params = append(params, strconv.Itoa(int(attr)))
+	}
+	if s.fg != nil {
+		params = append(params, s.fg.params(depth, false)...)
+	}
+	if s.bg != nil {
+		params = append(params, s.bg.params(depth, true)...)
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m" + text + "\x1b[0m"
+}
+
+// color returns the color which does not depend on the global color.NoColor.
+func (s Style) color() *color.Color {
+	c := color.New(s.attrs...)
+	c.EnableColor()
+	return c
+}
+
+// Theme defines the styles used for the colored output.
+type Theme struct {
+	Task    Style // task start records
+	Passed  Style // passed tasks and successful flows
+	Failed  Style // failed tasks, failed flows, errors, and panics
+	Skipped Style // skipped tasks and skip messages
+	NotRun  Style // tasks which were not run
+	Debug   Style // debug records
+	Info    Style // info records
+	Warn    Style // warning records
+}
+
+// DefaultTheme returns the default theme.
+func DefaultTheme() Theme {
+	return Theme{
+		Task:    NewStyle(color.FgBlue),
+		Passed:  NewStyle(color.FgGreen),
+		Failed:  NewStyle(color.FgRed),
+		Skipped: NewStyle(color.FgYellow),
+		NotRun:  NewStyle(color.FgGreen),
+		Debug:   NewStyle(color.FgHiBlack),
+		Info:    NewStyle(),
+		Warn:    NewStyle(color.FgHiYellow),
+	}
+}
+
+// HighContrastTheme returns a theme using bold and high intensity colors.
+func HighContrastTheme() Theme {
+	return Theme{
+		Task:    NewStyle(color.Bold, color.FgHiCyan),
+		Passed:  NewStyle(color.Bold, color.FgHiGreen),
+		Failed:  NewStyle(color.Bold, color.FgHiRed),
+		Skipped: NewStyle(color.Bold, color.FgHiYellow),
+		NotRun:  NewStyle(color.Bold, color.FgHiWhite),
+		Debug:   NewStyle(color.FgWhite),
+		Info:    NewStyle(color.FgHiWhite),
+		Warn:    NewStyle(color.Bold, color.FgHiYellow),
+	}
+}
+
+// ColorblindTheme returns a theme which does not rely on distinguishing
+// red from green.
+func ColorblindTheme() Theme {
+	return Theme{
+		Task:    NewStyle(color.FgHiBlack),
+		Passed:  NewStyle(color.FgBlue),
+		Failed:  NewStyle(color.Bold, color.FgMagenta),
+		Skipped: NewStyle(color.FgYellow),
+		NotRun:  NewStyle(color.FgCyan),
+		Debug:   NewStyle(color.FgHiBlack),
+		Info:    NewStyle(),
+		Warn:    NewStyle(color.FgHiYellow),
+	}
+}
+
+// themeFromEnv returns the theme named by the GOYEK_COLOR_THEME
+// environment variable or the default theme.
+func themeFromEnv() Theme {
+	switch os.Getenv(themeEnv) {
+	case "high-contrast":
+		return HighContrastTheme()
+	case "colorblind":
+		return ColorblindTheme()
+	default:
+		return DefaultTheme()
+	}
+}
+
+// status returns the name and the style of the task status.
+func (t Theme) status(s goyek.Status) (string, Style) {
+	switch s {
+	case goyek.StatusFailed:
+		return "FAIL", t.Failed
+	case goyek.StatusSkipped:
+		return "SKIP", t.Skipped
+	case goyek.StatusNotRun:
+		return "NOOP", t.NotRun
+	default:
+		return "PASS", t.Passed
+	}
+}
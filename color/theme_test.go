@@ -0,0 +1,110 @@
+package color_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	fatihcolor "github.com/fatih/color"
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestNewReportStatusWithTheme(t *testing.T) {
+	forceColor(t)
+
+	theme := goyekcolor.Theme{
+		Task:    goyekcolor.NewStyle(fatihcolor.FgCyan),
+		Passed:  goyekcolor.NewStyle(fatihcolor.FgHiGreen),
+		Failed:  goyekcolor.NewStyle(fatihcolor.FgMagenta),
+		Skipped: goyekcolor.NewStyle(fatihcolor.FgHiYellow),
+		NotRun:  goyekcolor.NewStyle(),
+	}
+	tests := []struct {
+		status goyek.Status
+		want   string
+	}{
+		{status: goyek.StatusPassed, want: "\x1b[92m----- PASS: task ("},
+		{status: goyek.StatusFailed, want: "\x1b[35m----- FAIL: task ("},
+		{status: goyek.StatusSkipped, want: "\x1b[93m----- SKIP: task ("},
+		{status: goyek.StatusNotRun, want: "\x1b[0m----- NOOP: task ("},
+	}
+	for _, tc := range tests {
+		t.Run(tc.status.String(), func(t *testing.T) {
+			out := &strings.Builder{}
+			mw := goyekcolor.NewReportStatus(goyekcolor.WithTheme(theme))
+			runner := mw(func(goyek.Input) goyek.Result { return goyek.Result{Status: tc.status} })
+			runner(goyek.Input{Output: out, TaskName: "task"})
+
+			got := out.String()
+			if !strings.HasPrefix(got, "\x1b[36m===== TASK  task\n") {
+				t.Errorf("unexpected task-start record: %q", got)
+			}
+			if !strings.Contains(got, tc.want) {
+				t.Errorf("output %q does not contain %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewReportFlowWithTheme(t *testing.T) {
+	forceColor(t)
+
+	out := &strings.Builder{}
+	mw := goyekcolor.NewReportFlow(goyekcolor.WithTheme(goyekcolor.ColorblindTheme()))
+	executor := mw(func(goyek.ExecuteInput) error { return errors.New("flow failed") })
+	_ = executor(goyek.ExecuteInput{Output: out})
+
+	if got, want := out.String(), "\x1b[1;1;35mflow failed\t"; !strings.HasPrefix(got, want) {
+		t.Errorf("output %q does not start with %q", got, want)
+	}
+}
+
+func TestThemeFromEnv(t *testing.T) {
+	forceColor(t)
+
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{env: "", want: ansiRed},
+		{env: "unknown", want: ansiRed},
+		{env: "high-contrast", want: "\x1b[1;91m"},
+		{env: "colorblind", want: "\x1b[1;35m"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.env, func(t *testing.T) {
+			t.Setenv("GOYEK_COLOR_THEME", tc.env)
+			out := &strings.Builder{}
+			runner := goyekcolor.ReportStatus(func(goyek.Input) goyek.Result { return goyek.Result{Status: goyek.StatusFailed} })
+			runner(goyek.Input{Output: out, TaskName: "task"})
+
+			if got := out.String(); !strings.Contains(got, tc.want+"----- FAIL") {
+				t.Errorf("output %q does not contain %q", got, tc.want+"----- FAIL")
+			}
+		})
+	}
+}
+
+func TestNewCodeLineLoggerWithTheme(t *testing.T) {
+	forceColor(t)
+
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(goyekcolor.NewCodeLineLogger(goyekcolor.WithTheme(goyekcolor.ColorblindTheme())))
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Error("failure")
+		},
+	})
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	if got, want := out.String(), "\x1b[1;35m      theme_test.go:"; !strings.HasPrefix(got, want) {
+		t.Errorf("output %q does not start with %q", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package color_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goyek/goyek/v3"
+
+	goyekcolor "github.com/goyek/x/color"
+)
+
+func TestCodeLineLoggerTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []goyekcolor.LoggerOption
+		want string
+	}{
+		{
+			name: "default layout",
+			opts: []goyekcolor.LoggerOption{goyekcolor.WithTimestamp("")},
+			want: `^      \d\d:\d\d:\d\d\.\d\d\d timestamp_test\.go:\d+: first\n          second\n$`,
+		},
+		{
+			name: "custom layout",
+			opts: []goyekcolor.LoggerOption{goyekcolor.WithTimestamp(time.RFC3339)},
+			want: `^      \d{4}-\d\d-\d\dT\S+ timestamp_test\.go:\d+: first\n          second\n$`,
+		},
+		{
+			name: "elapsed",
+			opts: []goyekcolor.LoggerOption{goyekcolor.WithElapsed()},
+			want: `^      \+\d+\.\d{3}s timestamp_test\.go:\d+: first\n          second\n$`,
+		},
+		{
+			name: "both",
+			opts: []goyekcolor.LoggerOption{goyekcolor.WithTimestamp(""), goyekcolor.WithElapsed()},
+			want: `^      \d\d:\d\d:\d\d\.\d\d\d \+\d+\.\d{3}s timestamp_test\.go:\d+: first\n          second\n$`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			flow := &goyek.Flow{}
+			out := &strings.Builder{}
+			flow.SetOutput(out)
+			flow.SetLogger(goyekcolor.NewCodeLineLogger(tc.opts...))
+			flow.Define(goyek.Task{
+				Name: "task",
+				Action: func(a *goyek.A) {
+					a.Log("first\nsecond")
+				},
+			})
+
+			_ = flow.Execute(context.Background(), []string{"task"})
+
+			if got := out.String(); !regexp.MustCompile(tc.want).MatchString(got) {
+				t.Errorf("output %q does not match %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCodeLineLoggerMiddleware(t *testing.T) {
+	logger := goyekcolor.NewCodeLineLogger(goyekcolor.WithElapsed())
+	flow := &goyek.Flow{}
+	out := &strings.Builder{}
+	flow.SetOutput(out)
+	flow.SetLogger(logger)
+	flow.Use(logger.Middleware)
+	flow.Define(goyek.Task{
+		Name: "task",
+		Action: func(a *goyek.A) {
+			a.Log("message")
+		},
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	_ = flow.Execute(context.Background(), []string{"task"})
+
+	want := regexp.MustCompile(`^      \+0\.0\d\ds timestamp_test\.go:\d+: message\n$`)
+	if got := out.String(); !want.MatchString(got) {
+		t.Errorf("output %q does not match %q", got, want)
+	}
+}
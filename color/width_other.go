@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package color
+
+// terminalWidth returns the number of columns of the terminal.
+// It is not supported on this platform.
+func terminalWidth(uintptr) (int, bool) {
+	return 0, false
+}
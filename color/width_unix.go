@@ -0,0 +1,14 @@
+//go:build unix
+
+package color
+
+import "golang.org/x/sys/unix"
+
+// terminalWidth returns the number of columns of the terminal.
+func terminalWidth(fd uintptr) (int, bool) {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ) //nolint:gosec // file descriptors fit in int
+	if err != nil || ws.Col == 0 {
+		return 0, false
+	}
+	return int(ws.Col), true
+}
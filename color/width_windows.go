@@ -0,0 +1,12 @@
+package color
+
+import "golang.org/x/sys/windows"
+
+// terminalWidth returns the number of columns of the console.
+func terminalWidth(fd uintptr) (int, bool) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0, false
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, true
+}
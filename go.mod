@@ -5,12 +5,14 @@ go 1.25.0
 require (
 	github.com/fatih/color v1.19.0
 	github.com/goyek/goyek/v3 v3.0.2-0.20260721120123-b290996180dc
+	github.com/mattn/go-isatty v0.0.20
 	github.com/mattn/go-shellwords v1.0.13
 	go.opentelemetry.io/contrib/propagators/envcar v0.69.0
 	go.opentelemetry.io/otel v1.44.0
 	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.44.0
 	go.opentelemetry.io/otel/sdk v1.44.0
 	go.opentelemetry.io/otel/trace v1.44.0
+	golang.org/x/sys v0.45.0
 )
 
 require (
@@ -19,8 +21,6 @@ require (
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/otel/metric v1.44.0 // indirect
-	golang.org/x/sys v0.45.0 // indirect
 )
@@ -0,0 +1,226 @@
+// Package diff computes line-based differences of texts.
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Op is the kind of a line edit.
+type Op int
+
+// Line edit kinds.
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is a single line of the edit script.
+type Edit struct {
+	Op   Op
+	Text string // the line including the trailing newline if present
+}
+
+// Lines returns the shortest edit script transforming the lines of a into
+// the lines of b computed using the linear space variant
+// of the Myers algorithm.
+func Lines(a, b []string) []Edit {
+	size := len(a) + len(b) + 4 //nolint:mnd // diagonals from -(n+m+1)/2-1 to (n+m+1)/2+1
+	d := &differ{a: a, b: b, vf: make([]int, size), vb: make([]int, size)}
+	d.compare(0, len(a), 0, len(b))
+	return d.edits
+}
+
+// differ computes the edit script. The furthest reaching paths
+// of the forward and backward searches are reused by each comparison.
+type differ struct {
+	a, b   []string
+	vf, vb []int
+	edits  []Edit
+}
+
+// compare appends the edit script transforming a[aLo:aHi] into b[bLo:bHi].
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.edits = append(d.edits, Edit{Op: Equal, Text: d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for _, line := range d.b[bLo:bHi] {
+			d.edits = append(d.edits, Edit{Op: Insert, Text: line})
+		}
+	case bLo == bHi:
+		for _, line := range d.a[aLo:aHi] {
+			d.edits = append(d.edits, Edit{Op: Delete, Text: line})
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for _, line := range d.a[x:u] {
+			d.edits = append(d.edits, Edit{Op: Equal, Text: line})
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for _, line := range d.a[aHi : aHi+suffix] {
+		d.edits = append(d.edits, Edit{Op: Equal, Text: line})
+	}
+}
+
+// middleSnake returns the start (x, y) and the end (u, v) of the snake
+// in the middle of a shortest edit script transforming a[aLo:aHi]
+// into b[bLo:bHi]. The first and the last lines have to differ.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2 //nolint:mnd // half of the longest script
+	off := maxD + 1
+	d.vf[off+1], d.vb[off+1] = 0, 0
+	for D := 0; D <= maxD; D++ {
+		// The forward search reaches diagonals k = x - y.
+		for k := -D; k <= D; k += 2 {
+			x := d.vf[off+k+1]
+			if k != -D && (k == D || d.vf[off+k-1] >= d.vf[off+k+1]) {
+				x = d.vf[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			d.vf[off+k] = x
+			if c := delta - k; odd && c >= -(D-1) && c <= D-1 && x+d.vb[off+c] >= n {
+				return aLo + sx, bLo + sy, aLo + x, bLo + y
+			}
+		}
+		// The backward search uses the reversed lines,
+		// its diagonal c corresponds to the forward diagonal delta - c.
+		for c := -D; c <= D; c += 2 {
+			x := d.vb[off+c+1]
+			if c != -D && (c == D || d.vb[off+c-1] >= d.vb[off+c+1]) {
+				x = d.vb[off+c-1] + 1
+			}
+			y := x - c
+			sx, sy := x, y
+			for x < n && y < m && d.a[aHi-x-1] == d.b[bHi-y-1] {
+				x++
+				y++
+			}
+			d.vb[off+c] = x
+			if k := delta - c; !odd && k >= -D && k <= D && x+d.vf[off+k] >= n {
+				return aHi - x, bHi - y, aHi - sx, bHi - sy
+			}
+		}
+	}
+	panic("diff: middle snake not found")
+}
+
+// SplitLines splits the text into lines keeping the trailing newlines.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// contextLines is the number of unchanged lines around the changes.
+const contextLines = 3
+
+// Unified returns the unified diff transforming the old text into the new one.
+// It returns an empty string if the texts are equal.
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	edits := Lines(SplitLines(oldText), SplitLines(newText))
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(edits); {
+		// Find the next change.
+		for start < len(edits) && edits[start].Op == Equal {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		// Extend the hunk while the changes are close enough.
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].Op != Equal {
+				end = i + 1
+			} else if i-end >= 2*contextLines {
+				break
+			}
+		}
+		from := max(start-contextLines, 0)
+		to := min(end+contextLines, len(edits))
+		writeHunk(sb, edits, from, to)
+		start = to
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, edits []Edit, from, to int) {
+	// Line numbers of the hunk start are the counts of preceding lines.
+	oldLine, newLine := 1, 1
+	for _, e := range edits[:from] {
+		if e.Op != Insert {
+			oldLine++
+		}
+		if e.Op != Delete {
+			newLine++
+		}
+	}
+	var oldCount, newCount int
+	for _, e := range edits[from:to] {
+		if e.Op != Insert {
+			oldCount++
+		}
+		if e.Op != Delete {
+			newCount++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+	for _, e := range edits[from:to] {
+		switch e.Op {
+		case Equal:
+			sb.WriteByte(' ')
+		case Delete:
+			sb.WriteByte('-')
+		case Insert:
+			sb.WriteByte('+')
+		}
+		sb.WriteString(e.Text)
+		if !strings.HasSuffix(e.Text, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return strconv.Itoa(line)
+	default:
+		return fmt.Sprintf("%d,%d", line, count)
+	}
+}
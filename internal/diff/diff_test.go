@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "change",
+			old:  "a\nb\nc\n",
+			new:  "a\nx\nc\n",
+			want: "--- want\n+++ got\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name: "insert into empty",
+			old:  "",
+			new:  "a\n",
+			want: "--- want\n+++ got\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name: "missing newline",
+			old:  "a\n",
+			new:  "a",
+			want: "--- want\n+++ got\n@@ -1 +1 @@\n-a\n+a\n\\ No newline at end of file\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:  "0\n2\n3\n4\n5\n6\n7\n8\n9\n11\n",
+			want: "--- want\n+++ got\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+11\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Unified("want", "got", tc.old, tc.new)
+			if got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	a := strings.Split("A B C A B B A", " ")
+	b := strings.Split("C B A B A C", " ")
+
+	edits := Lines(a, b)
+
+	var gotA, gotB []string
+	changes := 0
+	for _, e := range edits {
+		if e.Op != Insert {
+			gotA = append(gotA, e.Text)
+		}
+		if e.Op != Delete {
+			gotB = append(gotB, e.Text)
+		}
+		if e.Op != Equal {
+			changes++
+		}
+	}
+	if strings.Join(gotA, " ") != strings.Join(a, " ") || strings.Join(gotB, " ") != strings.Join(b, " ") {
+		t.Fatalf("edit script does not reproduce the inputs: %v", edits)
+	}
+	if want := 5; changes != want {
+		t.Errorf("got %d changes, want %d", changes, want)
+	}
+}
+
+func TestLinesShortest(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // deterministic test data
+	randomLines := func() []string {
+		lines := make([]string, rnd.IntN(12))
+		for i := range lines {
+			lines[i] = string(rune('A' + rnd.IntN(3)))
+		}
+		return lines
+	}
+	for range 2000 {
+		a, b := randomLines(), randomLines()
+
+		edits := Lines(a, b)
+
+		var gotA, gotB []string
+		changes := 0
+		for _, e := range edits {
+			if e.Op != Insert {
+				gotA = append(gotA, e.Text)
+			}
+			if e.Op != Delete {
+				gotB = append(gotB, e.Text)
+			}
+			if e.Op != Equal {
+				changes++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("edit script of %q and %q does not reproduce the inputs: %v", a, b, edits)
+		}
+		if want := len(a) + len(b) - 2*lcsLen(a, b); changes != want {
+			t.Fatalf("got %d changes for %q and %q, want %d", changes, a, b, want)
+		}
+	}
+}
+
+func TestLinesDifferent(t *testing.T) {
+	const n = 5000
+	a, b := make([]string, n), make([]string, n)
+	for i := range n {
+		a[i], b[i] = "a\n", "b\n"
+	}
+
+	if got := len(Lines(a, b)); got != 2*n {
+		t.Errorf("got %d edits, want %d", got, 2*n)
+	}
+}
+
+// lcsLen returns the length of the longest common subsequence.
+func lcsLen(a, b []string) int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(cur[j], prev[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}